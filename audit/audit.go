@@ -0,0 +1,270 @@
+// Package audit 实现仅追加、防篡改的审计日志。
+//
+// 审计日志记录所有变更操作（存储、元信息存储、删除等），与运行日志分开存放。
+// 每条记录包含前一条记录的哈希，形成哈希链：任何记录被修改、删除或重排，
+// 都会使其后的链校验失败。
+//
+// 存储格式为每行一条JSON记录。
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cxio/archives/utils"
+)
+
+// 操作类型。
+const (
+	OpStore      = "store"
+	OpStoreMeta  = "store-meta"
+	OpDelete     = "delete"
+	OpDeleteMeta = "delete-meta"
+)
+
+// 记录的尺寸限制。
+// 单个字段经JSON转义后至多膨胀为6倍，4个字段加上固定部分，
+// 完整记录总在 MaxRecordSize 之内。
+const (
+	MaxFieldSize  = 1024     // 字段（操作、文档ID、操作者、IP）的最大字节数
+	MaxRecordSize = 64 << 10 // 一行记录的最大字节数
+)
+
+var (
+	// ErrBroken 哈希链断裂（日志被篡改）。
+	ErrBroken = errors.New("audit chain broken")
+
+	// ErrFieldSize 记录字段过长。
+	ErrFieldSize = errors.New("audit field too long")
+)
+
+// Entry 一条审计记录。
+type Entry struct {
+	Seq   uint64 `json:"seq"`   // 序号，从1开始
+	Time  int64  `json:"time"`  // 时间（Unix纳秒）
+	Op    string `json:"op"`    // 操作类型
+	DocID string `json:"id"`    // 文档ID
+	Actor string `json:"actor"` // 操作者（密钥ID或用户名）
+	IP    string `json:"ip"`    // 客户端IP
+	Prev  string `json:"prev"`  // 前一条记录的哈希
+	Hash  string `json:"hash"`  // 本记录的哈希
+}
+
+// 计算记录的链哈希。
+// 哈希的对象为 Hash 置空后的JSON编码：字段顺序固定，字符串经转义，
+// 因此不同的字段值不会产生相同的编码。
+func (e *Entry) digest() string {
+	c := *e
+	c.Hash = ""
+	buf, _ := json.Marshal(&c)
+
+	return hex.EncodeToString(utils.HashSHA3(buf))
+}
+
+// Log 审计日志。
+// 并发安全，每条记录写入后即同步到磁盘。
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	seq  uint64
+	last string
+}
+
+// TornSuffix 不完整记录的隔离文件后缀。
+const TornSuffix = ".torn"
+
+// Open 打开（或创建）审计日志文件。
+// 打开时校验整条哈希链，链断裂时返回 ErrBroken。
+//
+// 末行不完整（缺少换行）时视为写入中途崩溃：该记录未确认写入，
+// 将其移存到 path+TornSuffix 后截除，不视为链断裂。
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	size, err := trimTail(f, path+TornSuffix)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	l := &Log{file: f, size: size}
+
+	err = scan(f, func(e *Entry) error {
+		l.seq, l.last = e.Seq, e.Hash
+		return nil
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Append 追加一条记录。
+// 序号、时间和哈希由日志自动填充。
+// 任一字段超过 MaxFieldSize 时返回 ErrFieldSize，不写入。
+func (l *Log) Append(op, docID, actor, ip string) (*Entry, error) {
+	for _, s := range []string{op, docID, actor, ip} {
+		if len(s) > MaxFieldSize {
+			return nil, ErrFieldSize
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := &Entry{
+		Seq:   l.seq + 1,
+		Time:  time.Now().UnixNano(),
+		Op:    op,
+		DocID: docID,
+		Actor: actor,
+		IP:    ip,
+		Prev:  l.last,
+	}
+	e.Hash = e.digest()
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, '\n')
+
+	if _, err := l.file.Write(buf); err != nil {
+		// 撤除可能的部分写入，以免后续记录接在残缺的行后
+		l.file.Truncate(l.size)
+		return nil, err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.file.Truncate(l.size)
+		return nil, err
+	}
+	l.size += int64(len(buf))
+	l.seq, l.last = e.Seq, e.Hash
+
+	return e, nil
+}
+
+// Head 返回最新记录的序号和哈希。
+// 外部定期留存此值，可防止日志尾部被整体截断而不被察觉。
+func (l *Log) Head() (uint64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq, l.last
+}
+
+// Query 查询符合条件的记录。
+// match 为nil时返回全部记录。查询会同时校验哈希链。
+func (l *Log) Query(match func(*Entry) bool) ([]*Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var list []*Entry
+	err := scan(io.NewSectionReader(l.file, 0, 1<<62), func(e *Entry) error {
+		if match == nil || match(e) {
+			list = append(list, e)
+		}
+		return nil
+	})
+	return list, err
+}
+
+// Close 关闭日志。
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// ByDoc 构造按文档ID匹配的查询条件。
+func ByDoc(docID string) func(*Entry) bool {
+	return func(e *Entry) bool { return e.DocID == docID }
+}
+
+// 截除文件末尾不完整的记录，追加保存到 torn 文件。
+// 完整的记录总以换行结尾。返回截除后的文件大小。
+func trimTail(f *os.File, torn string) (int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	end := fi.Size()
+	pos := end
+	buf := make([]byte, 4096)
+
+	// 自尾部向前查找最后的换行
+	for pos > 0 {
+		n := min(int64(len(buf)), pos)
+		pos -= n
+
+		if _, err := f.ReadAt(buf[:n], pos); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			pos += int64(i) + 1
+			break
+		}
+	}
+	if pos == end {
+		return end, nil
+	}
+	tail := make([]byte, end-pos)
+
+	if _, err := f.ReadAt(tail, pos); err != nil {
+		return 0, err
+	}
+	if err := appendFile(torn, append(tail, '\n')); err != nil {
+		return 0, err
+	}
+	return pos, f.Truncate(pos)
+}
+
+// 追加数据到文件并同步。
+func appendFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// 顺序读取并校验记录，对每条记录调用 fn。
+// 超过 MaxRecordSize 的行不可能由 Append 写入，视为链断裂。
+func scan(r io.Reader, fn func(*Entry) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 4096), MaxRecordSize)
+	var seq uint64
+	var prev string
+
+	for sc.Scan() {
+		e := new(Entry)
+		if err := json.Unmarshal(sc.Bytes(), e); err != nil {
+			return fmt.Errorf("%w: record %d: %v", ErrBroken, seq+1, err)
+		}
+		if e.Seq != seq+1 || e.Prev != prev || e.Hash != e.digest() {
+			return fmt.Errorf("%w: at record %d", ErrBroken, seq+1)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+		seq, prev = e.Seq, e.Hash
+	}
+	if err := sc.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("%w: record %d: %v", ErrBroken, seq+1, err)
+	}
+	return sc.Err()
+}
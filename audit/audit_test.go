@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 创建含若干记录的审计日志，返回其路径。
+func newLog(t *testing.T, ops ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for i, op := range ops {
+		if _, err := l.Append(op, "doc"+string(rune('a'+i%2)), "alice", "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// 读取日志的各行。
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.SplitAfter(string(data), "\n")
+}
+
+func TestChain(t *testing.T) {
+	path := newLog(t, OpStore, OpStoreMeta, OpDelete)
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+
+	list, err := l.Query(nil)
+	if err != nil || len(list) != 3 {
+		t.Fatalf("Query(all) = %d, %v", len(list), err)
+	}
+	seq, head := l.Head()
+	if seq != 3 || head != list[2].Hash {
+		t.Errorf("Head() = %d %s, want 3 %s", seq, head, list[2].Hash)
+	}
+	e, err := l.Append(OpDeleteMeta, "docb", "bob", "::1")
+	if err != nil || e.Seq != 4 || e.Prev != head {
+		t.Fatalf("Append = %+v, %v", e, err)
+	}
+	list, err = l.Query(ByDoc("docb"))
+	if err != nil || len(list) != 2 || list[0].Op != OpStoreMeta || list[1].Actor != "bob" {
+		t.Errorf("Query(ByDoc) = %+v, %v", list, err)
+	}
+}
+
+func TestTamper(t *testing.T) {
+	tests := map[string]func([]string) []string{
+		"edit": func(ls []string) []string {
+			ls[1] = strings.Replace(ls[1], "alice", "mallory", 1)
+			return ls
+		},
+		"delete": func(ls []string) []string {
+			return append(ls[:1:1], ls[2:]...)
+		},
+		"swap": func(ls []string) []string {
+			ls[0], ls[1] = ls[1], ls[0]
+			return ls
+		},
+		"garbage": func(ls []string) []string {
+			ls[1] = "{\n"
+			return ls
+		},
+		// 字段间移动内容，换行分隔的哈希无法察觉
+		"shift": func(ls []string) []string {
+			ls[1] = strings.Replace(ls[1], `"actor":"alice","ip":"10.0.0.1"`, `"actor":"alice\n10.0.0.1","ip":""`, 1)
+			return ls
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := newLog(t, OpStore, OpStore, OpStore)
+			lines := fn(readLines(t, path))
+			os.WriteFile(path, []byte(strings.Join(lines, "")), 0o640)
+
+			if l, err := Open(path); !errors.Is(err, ErrBroken) {
+				if l != nil {
+					l.Close()
+				}
+				t.Errorf("Open = %v, want ErrBroken", err)
+			}
+		})
+	}
+}
+
+func TestTornTail(t *testing.T) {
+	path := newLog(t, OpStore, OpStore)
+	data, _ := os.ReadFile(path)
+	whole := readLines(t, path)
+
+	// 模拟追加第三条记录时崩溃
+	torn := []byte(`{"seq":3,"time":17`)
+	os.WriteFile(path, append(bytes.Clone(data), torn...), 0o640)
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if seq, _ := l.Head(); seq != 2 {
+		t.Errorf("Head() seq = %d, want 2", seq)
+	}
+	if got, _ := os.ReadFile(path + TornSuffix); !bytes.Equal(got, append(torn, '\n')) {
+		t.Errorf("torn file = %q", got)
+	}
+	if _, err := l.Append(OpDelete, "doca", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer l.Close()
+
+	if seq, _ := l.Head(); seq != 3 {
+		t.Errorf("Head() seq = %d, want 3", seq)
+	}
+	if lines := readLines(t, path); lines[0] != whole[0] || lines[1] != whole[1] {
+		t.Error("complete records changed")
+	}
+}
+
+func TestTornMiddle(t *testing.T) {
+	path := newLog(t, OpStore, OpStore, OpStore)
+	lines := readLines(t, path)
+
+	// 中部的不完整记录不是崩溃所致
+	lines[1] = lines[1][:20] + "\n"
+	os.WriteFile(path, []byte(strings.Join(lines, "")), 0o640)
+
+	if _, err := Open(path); !errors.Is(err, ErrBroken) {
+		t.Errorf("Open = %v, want ErrBroken", err)
+	}
+	if _, err := os.Stat(path + TornSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Error("torn file created for mid-file damage")
+	}
+}
+
+func TestRecordSize(t *testing.T) {
+	path := newLog(t, OpStore)
+
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Repeat("a", 70000)
+	if _, err := l.Append(OpStore, "doca", long, ""); !errors.Is(err, ErrFieldSize) {
+		t.Errorf("Append(long actor) = %v, want ErrFieldSize", err)
+	}
+	// 最长字段经转义后仍可读回
+	max := strings.Repeat("<", MaxFieldSize)
+	if _, err := l.Append(max, max, max, max); err != nil {
+		t.Fatalf("Append(max fields): %v", err)
+	}
+	l.Close()
+
+	if l, err = Open(path); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if seq, _ := l.Head(); seq != 2 {
+		t.Errorf("Head() seq = %d, want 2", seq)
+	}
+	l.Close()
+
+	// 超长的行不是 Append 所写
+	data, _ := os.ReadFile(path)
+	data = append(data, strings.Repeat("x", MaxRecordSize+1)+"\n"...)
+	os.WriteFile(path, data, 0o640)
+
+	if _, err := Open(path); !errors.Is(err, ErrBroken) {
+		t.Errorf("Open(long line) = %v, want ErrBroken", err)
+	}
+}
@@ -0,0 +1,166 @@
+// Package codec 管理存储和传输的压缩编解码器。
+//
+// 编解码器以名称注册，调用方只按名称选用，新增编解码器无需修改调用处。
+// 内置 none 和 gzip，其它（如 zstd、lz4）由引入实现的一方注册。
+//
+// 选用规则有两层：
+//   - 按内容类型（MIME）的策略，决定文档是否值得压缩、用哪种压缩。
+//   - 与对端协商，取双方都支持的首选编解码器。
+package codec
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// 内置编解码器名称。
+const (
+	None = "none"
+	Gzip = "gzip"
+)
+
+// ErrUnknown 未注册的编解码器。
+var ErrUnknown = errors.New("unknown codec")
+
+// Codec 压缩编解码器。
+type Codec interface {
+	// Name 编解码器名称。
+	Name() string
+
+	// NewWriter 创建压缩写入器，关闭时完成压缩流。
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// NewReader 创建解压读取器。
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	mu     sync.RWMutex
+	codecs = make(map[string]Codec)
+)
+
+func init() {
+	Register(noneCodec{})
+	Register(gzipCodec{})
+}
+
+// Register 注册一个编解码器。
+// 同名的重复注册会覆盖之前的定义。
+func Register(c Codec) {
+	mu.Lock()
+	defer mu.Unlock()
+	codecs[c.Name()] = c
+}
+
+// Get 按名称获取编解码器。
+func Get(name string) (Codec, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknown, name)
+	}
+	return c, nil
+}
+
+// Names 返回已注册的编解码器名称（已排序）。
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]string, 0, len(codecs))
+	for n := range codecs {
+		list = append(list, n)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// Negotiate 与对端协商编解码器。
+// prefs 为本地的优先顺序，peer 为对端支持的列表。
+// 返回第一个双方都支持且已注册的编解码器，没有时返回 none。
+func Negotiate(prefs, peer []string) string {
+	accept := make(map[string]bool, len(peer))
+	for _, n := range peer {
+		accept[n] = true
+	}
+	for _, n := range prefs {
+		if _, err := Get(n); err == nil && accept[n] {
+			return n
+		}
+	}
+	return None
+}
+
+// Policy 按内容类型选用编解码器的策略。
+// 键为MIME类型，可用 "type/*" 匹配一类，"*" 匹配全部。
+type Policy map[string]string
+
+// DefaultPolicy 默认策略。
+// 文本类内容压缩，本身已压缩的媒体和归档格式不压缩。
+var DefaultPolicy = Policy{
+	"text/*":                 Gzip,
+	"application/json":       Gzip,
+	"application/xml":        Gzip,
+	"application/javascript": Gzip,
+	"image/svg+xml":          Gzip,
+	"*":                      None,
+}
+
+// Select 选用内容类型对应的编解码器名称。
+// 依次匹配完整类型、类型大类和 "*"，均无匹配时返回 none。
+func (p Policy) Select(mime string) string {
+	mime, _, _ = strings.Cut(strings.ToLower(mime), ";")
+	mime = strings.TrimSpace(mime)
+
+	if n, ok := p[mime]; ok {
+		return n
+	}
+	if major, _, ok := strings.Cut(mime, "/"); ok {
+		if n, ok := p[major+"/*"]; ok {
+			return n
+		}
+	}
+	if n, ok := p["*"]; ok {
+		return n
+	}
+	return None
+}
+
+// 不压缩。
+type noneCodec struct{}
+
+func (noneCodec) Name() string { return None }
+
+func (noneCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (noneCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// gzip 压缩。
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return Gzip }
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
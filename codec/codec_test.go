@@ -0,0 +1,87 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		mime string
+		want string
+	}{
+		{"text/plain", Gzip},
+		{"text/html; charset=utf-8", Gzip},
+		{"Text/CSS", Gzip},
+		{" application/json ", Gzip},
+		{"application/json;charset=utf-8", Gzip},
+		{"image/svg+xml", Gzip},
+		{"image/png", None},
+		{"application/zip", None},
+		{"video/mp4", None},
+		{"", None},
+		{"garbage", None},
+	}
+	for _, tt := range tests {
+		if got := DefaultPolicy.Select(tt.mime); got != tt.want {
+			t.Errorf("DefaultPolicy.Select(%q) = %s, want %s", tt.mime, got, tt.want)
+		}
+	}
+
+	// 无 "*" 条目时回退到 none，完整类型优先于大类
+	p := Policy{"image/*": Gzip, "image/png": None}
+	for mime, want := range map[string]string{"image/bmp": Gzip, "image/png": None, "text/plain": None} {
+		if got := p.Select(mime); got != want {
+			t.Errorf("Select(%q) = %s, want %s", mime, got, want)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		prefs, peer []string
+		want        string
+	}{
+		{[]string{Gzip, None}, []string{None, Gzip}, Gzip},
+		{[]string{"zstd", Gzip}, []string{"zstd", Gzip}, Gzip}, // zstd 未注册
+		{[]string{Gzip}, []string{"zstd"}, None},
+		{nil, []string{Gzip}, None},
+		{[]string{None, Gzip}, []string{Gzip, None}, None},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.prefs, tt.peer); got != tt.want {
+			t.Errorf("Negotiate(%v, %v) = %s, want %s", tt.prefs, tt.peer, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("archives ", 1000))
+
+	for _, name := range Names() {
+		c, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		w, _ := c.NewWriter(&buf)
+		w.Write(data)
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: close: %v", name, err)
+		}
+		r, err := c.NewReader(&buf)
+		if err != nil {
+			t.Fatalf("%s: reader: %v", name, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: round trip failed: %v", name, err)
+		}
+	}
+	if _, err := Get("zstd"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Get(zstd) = %v, want ErrUnknown", err)
+	}
+}
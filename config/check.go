@@ -0,0 +1,147 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cxio/archives/utils"
+)
+
+// 有效的日志级别、格式和输出。
+var (
+	logLevels  = []string{"trace", "debug", "info", "warn", "warning", "error", "fatal", "panic"}
+	logFormats = []string{"text", "json"}
+	logOutputs = []string{"file", "syslog", "journal"}
+)
+
+// Validate 检查配置的有效性。
+// 返回全部问题（以 errors.Join 合并），每条说明了出错的条目和原因。
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.DepotPort <= 0 || c.DepotPort > 65535 {
+		errs = append(errs, fmt.Errorf("depot_port: %d is not a valid port (1-65535)", c.DepotPort))
+	}
+	if err := checkWritable(c.DataRoot); err != nil {
+		errs = append(errs, fmt.Errorf("data_root: %w", err))
+	}
+	if err := checkKeyFile(c.KeyFile); err != nil {
+		errs = append(errs, fmt.Errorf("key_file: %w", err))
+	}
+	if !slices.Contains(logLevels, c.LogLevel) {
+		errs = append(errs, fmt.Errorf("log_level: %q is not one of %s", c.LogLevel, strings.Join(logLevels, ", ")))
+	}
+	if !slices.Contains(logFormats, c.LogFormat) {
+		errs = append(errs, fmt.Errorf("log_format: %q is not one of %s", c.LogFormat, strings.Join(logFormats, ", ")))
+	}
+	if !slices.Contains(logOutputs, c.LogOutput) {
+		errs = append(errs, fmt.Errorf("log_output: %q is not one of %s", c.LogOutput, strings.Join(logOutputs, ", ")))
+	}
+	if c.LogOutput == "file" {
+		if err := checkWritable(c.LogRoot); err != nil {
+			errs = append(errs, fmt.Errorf("log_root: %w", err))
+		}
+	}
+	if algos := utils.AlgoNames(); !slices.Contains(algos, c.HashAlgo) {
+		errs = append(errs, fmt.Errorf("hash_algo: %q is not one of %s", c.HashAlgo, strings.Join(algos, ", ")))
+	}
+	if c.DiskHighWatermark < 0 || c.DiskHighWatermark > 100 {
+		errs = append(errs, fmt.Errorf("disk_high_watermark: %d is not a percentage (0-100)", c.DiskHighWatermark))
+	}
+	if err := checkDebug(c.DebugAddr, c.DebugToken); err != nil {
+		errs = append(errs, fmt.Errorf("debug_addr: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// 检查节点密钥文件的路径。
+// 文件可以不存在（启动时创建），但不能是目录。
+func checkKeyFile(path string) error {
+	if path == "" {
+		return errors.New("path is empty")
+	}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
+// 检查调试服务的地址。
+// 调试接口暴露进程内部信息，只允许本机地址，除非设置了访问令牌。
+func checkDebug(addr, token string) error {
+	if addr == "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if token != "" || host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("%s is not a loopback address, debug_token is required", addr)
+}
+
+// 检查配置文件中的条目名是否都有效。
+func checkKeys(m map[string]interface{}) error {
+	t := reflect.TypeOf(Config{})
+	known := make(map[string]bool, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[tag] = true
+	}
+	var bad []string
+	for k := range m {
+		if !known[k] {
+			bad = append(bad, k)
+		}
+	}
+	if len(bad) > 0 {
+		slices.Sort(bad)
+		return fmt.Errorf("unknown config keys: %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// 检查目录是否可写。
+// 目录不存在时，检查其最近的已存在上级目录是否可写（启动时会自动创建）。
+func checkWritable(dir string) error {
+	if dir == "" {
+		return errors.New("path is empty")
+	}
+	p := filepath.Clean(dir)
+
+	for {
+		fi, err := os.Stat(p)
+		if err == nil {
+			if !fi.IsDir() {
+				return fmt.Errorf("%s is not a directory", p)
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		up := filepath.Dir(p)
+		if up == p {
+			return fmt.Errorf("no existing parent of %s", dir)
+		}
+		p = up
+	}
+	f, err := os.CreateTemp(p, ".check-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,146 @@
+// Package config 载入存档服务的配置。
+//
+// 配置文件为 hjson 格式（默认 ./config.hjson），未配置的条目采用默认值。
+// 每个条目都可由环境变量覆盖，变量名为 ARCHIVES_ 前缀加大写的条目名，
+// 如 ARCHIVES_LOG_LEVEL。优先级：命令行参数 > 环境变量 > 配置文件 > 默认值。
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hjson/hjson-go"
+)
+
+// 默认配置值。
+const (
+	DepotPort = 17799
+	DataRoot  = "./_data"
+	KeyFile   = "./_keys/node.key"
+	LogLevel  = "info"
+	LogRoot   = "./_logs"
+	LogFormat = "text"
+	LogOutput = "file"
+	HashAlgo  = "sha3-256"
+
+	DiskHighWatermark = 95
+)
+
+// Config 服务配置。
+type Config struct {
+	DepotPort int    `json:"depot_port"` // 驿站服务端口
+	DataRoot  string `json:"data_root"`  // 存档数据根目录
+	KeyFile   string `json:"key_file"`   // 节点身份密钥文件，不存在时自动创建
+	LogLevel  string `json:"log_level"`  // 日志级别：debug, info, warn, error
+	LogRoot   string `json:"log_root"`   // 日志存储根路径
+	LogFormat string `json:"log_format"` // 日志格式：text, json
+	LogOutput string `json:"log_output"` // 日志输出：file, syslog, journal
+	HashAlgo  string `json:"hash_algo"`  // 新文档ID的哈希算法：sha3-256, sha2-256, blake3
+
+	DiskHighWatermark int `json:"disk_high_watermark"` // 磁盘使用率高水位（百分比），0为不限
+
+	DebugAddr  string `json:"debug_addr"`  // 调试服务（pprof/expvar/日志级别）监听地址，空为不启用
+	DebugToken string `json:"debug_token"` // 调试服务的访问令牌，非本机地址时必须
+}
+
+// Default 返回默认配置。
+func Default() *Config {
+	return &Config{
+		DepotPort: DepotPort,
+		DataRoot:  DataRoot,
+		KeyFile:   KeyFile,
+		LogLevel:  LogLevel,
+		LogRoot:   LogRoot,
+		LogFormat: LogFormat,
+		LogOutput: LogOutput,
+		HashAlgo:  HashAlgo,
+
+		DiskHighWatermark: DiskHighWatermark,
+	}
+}
+
+// 环境变量名前缀。
+const EnvPrefix = "ARCHIVES_"
+
+// Load 载入配置文件，并应用环境变量的覆盖。
+// 文件中未出现的条目保持默认值，未知的条目（如拼写错误）视为错误。
+// path 为空时不读取文件，仅采用默认值和环境变量（适于容器化部署）。
+// 载入后的配置应再经 Validate 检查。
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := decode(data, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// 解码 hjson 数据到配置。
+// hjson-go v3 只能解码到通用的 map，故经 JSON 中转到结构体。
+func decode(data []byte, cfg *Config) error {
+	var m map[string]interface{}
+
+	if err := hjson.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if err := checkKeys(m); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, cfg)
+}
+
+// 以环境变量覆盖配置条目。
+// 变量名由条目的 json 标签转换而来。设置为空值的变量同样生效，
+// 如 ARCHIVES_DEBUG_ADDR= 可关闭配置文件中启用的调试服务。
+func applyEnv(cfg *Config) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		name := EnvPrefix + strings.ToUpper(tag)
+		val, ok := os.LookupEnv(name)
+
+		if tag == "" || !ok {
+			continue
+		}
+		f := v.Field(i)
+
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(val)
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			f.SetInt(n)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			f.SetBool(b)
+		default:
+			return fmt.Errorf("%s: unsupported type %s", name, f.Kind())
+		}
+	}
+	return nil
+}
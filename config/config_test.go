@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 写入临时配置文件，返回其路径。
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.hjson")
+
+	if err := os.WriteFile(p, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadRepoConfig(t *testing.T) {
+	cfg, err := Load("../config.hjson")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *cfg != *Default() {
+		t.Errorf("repo config = %+v, want defaults %+v", *cfg, *Default())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want func(*Config)
+	}{
+		{"empty", "{}", func(*Config) {}},
+		{"braceless", "log_level: debug", func(c *Config) { c.LogLevel = "debug" }},
+		{"partial", `{
+			depot_port: 8080
+			# 注释
+			log_format: json
+			hash_algo: blake3
+			disk_high_watermark: 80
+		}`, func(c *Config) {
+			c.DepotPort = 8080
+			c.LogFormat = "json"
+			c.HashAlgo = "blake3"
+			c.DiskHighWatermark = 80
+		}},
+		{"debug", `{debug_addr: "0.0.0.0:6060", debug_token: "s3cret"}`, func(c *Config) {
+			c.DebugAddr = "0.0.0.0:6060"
+			c.DebugToken = "s3cret"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeConfig(t, tt.text))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			want := Default()
+			tt.want(want)
+
+			if *cfg != *want {
+				t.Errorf("got %+v, want %+v", *cfg, *want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"unknown keys", `{log_levle: "debug", data_rot: "x"}`, "unknown config keys: data_rot, log_levle"},
+		{"wrong type", `{depot_port: "http"}`, "depot_port"},
+		{"syntax", "{depot_port: [", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.text))
+			if err == nil {
+				t.Fatal("Load succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("ARCHIVES_DEPOT_PORT", "9000")
+	t.Setenv("ARCHIVES_LOG_OUTPUT", "journal")
+
+	cfg, err := Load(writeConfig(t, `{depot_port: 8080, log_level: "debug"}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DepotPort != 9000 || cfg.LogOutput != "journal" || cfg.LogLevel != "debug" {
+		t.Errorf("got %+v", *cfg)
+	}
+
+	// 空值同样生效
+	t.Setenv("ARCHIVES_LOG_FORMAT", "")
+	cfg, err = Load(writeConfig(t, `{log_format: "json"}`))
+	if err != nil || cfg.LogFormat != "" {
+		t.Errorf("empty ARCHIVES_LOG_FORMAT: got %q, %v", cfg.LogFormat, err)
+	}
+
+	// 无配置文件
+	t.Setenv("ARCHIVES_LOG_LEVEL", "warn")
+	cfg, err = Load("")
+	if err != nil || cfg.DepotPort != 9000 || cfg.LogLevel != "warn" || cfg.DataRoot != DataRoot {
+		t.Errorf("Load(\"\") = %+v, %v", cfg, err)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.hjson")); err == nil {
+		t.Error("Load(missing file) succeeded")
+	}
+
+	t.Setenv("ARCHIVES_DEPOT_PORT", "http")
+	if _, err := Load(writeConfig(t, "{}")); err == nil || !strings.Contains(err.Error(), "ARCHIVES_DEPOT_PORT") {
+		t.Errorf("bad integer: got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0o644)
+
+	tests := []struct {
+		name string
+		set  func(*Config)
+		want []string // 错误中应出现的条目名，空为有效
+	}{
+		{"default", func(*Config) {}, nil},
+		{"missing dirs", func(c *Config) {
+			c.DataRoot = filepath.Join(dir, "a", "b")
+			c.LogRoot = filepath.Join(dir, "c")
+		}, nil},
+		{"port", func(c *Config) { c.DepotPort = 70000 }, []string{"depot_port"}},
+		{"key file empty", func(c *Config) { c.KeyFile = "" }, []string{"key_file"}},
+		{"key file is dir", func(c *Config) { c.KeyFile = dir }, []string{"key_file"}},
+		{"data root is file", func(c *Config) { c.DataRoot = file }, []string{"data_root"}},
+		{"log root unused", func(c *Config) { c.LogOutput, c.LogRoot = "syslog", file }, nil},
+		{"log root is file", func(c *Config) { c.LogRoot = file }, []string{"log_root"}},
+		{"log", func(c *Config) {
+			c.LogLevel, c.LogFormat, c.LogOutput = "verbose", "xml", "stdout"
+		}, []string{"log_level", "log_format", "log_output"}},
+		{"watermark", func(c *Config) { c.DiskHighWatermark = 101 }, []string{"disk_high_watermark"}},
+		{"blake3", func(c *Config) { c.HashAlgo = "blake3" }, nil},
+		{"hash algo", func(c *Config) { c.HashAlgo = "md5" }, []string{"hash_algo"}},
+		{"debug loopback", func(c *Config) { c.DebugAddr = "127.0.0.1:6060" }, nil},
+		{"debug localhost", func(c *Config) { c.DebugAddr = "localhost:6060" }, nil},
+		{"debug public", func(c *Config) { c.DebugAddr = ":6060" }, []string{"debug_addr"}},
+		{"debug token", func(c *Config) { c.DebugAddr, c.DebugToken = ":6060", "s3cret" }, nil},
+		{"debug bad addr", func(c *Config) { c.DebugAddr = "6060" }, []string{"debug_addr"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.DataRoot = filepath.Join(dir, "data")
+			cfg.LogRoot = filepath.Join(dir, "logs")
+			tt.set(cfg)
+
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate succeeded, want errors for %v", tt.want)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Errorf("got %d errors, want %d:\n%v", len(lines), len(tt.want), err)
+			}
+			for _, key := range tt.want {
+				if !strings.Contains(err.Error(), key+":") {
+					t.Errorf("error does not mention %s:\n%v", key, err)
+				}
+			}
+		})
+	}
+}
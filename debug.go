@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/cxio/archives/logs"
+)
+
+func init() {
+	expvar.NewString("version").Set(Version)
+}
+
+// 启动调试服务（pprof、expvar 和日志级别）。
+// addr 为空时不启动。token 非空时，请求须携带 "Authorization: Bearer <token>"，
+// 且仅此时才开放日志级别的设置。
+func startDebug(addr, token string) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	srv := &http.Server{Handler: debugAuth(token, debugMux(token != ""))}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		logs.App.Errorf("debug listener: %v", err)
+		return nil
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logs.App.Errorf("debug server: %v", err)
+		}
+	}()
+	logs.App.Infof("debug server listening on %s", ln.Addr())
+
+	return srv
+}
+
+// 调试服务的路由。
+// write 为是否注册可变更状态的接口（设置日志级别），
+// 仅在配置了令牌时开放，无令牌的调试服务只读。
+func debugMux(write bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("GET /debug/loglevel", getLogLevel)
+
+	if write {
+		mux.HandleFunc("PUT /debug/loglevel", putLogLevel)
+	}
+
+	return mux
+}
+
+// 查询日志级别。
+func getLogLevel(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, logs.Level())
+}
+
+// 设置日志级别，请求体为级别名称（如 debug）。
+// 与 SIGUSR1 相同，变更以警告级别记录。
+func putLogLevel(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := logs.SetLevel(strings.TrimSpace(string(body))); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logs.App.Warnf("log level set to %s via debug server", logs.Level())
+	fmt.Fprintln(w, logs.Level())
+}
+
+// 调试服务的令牌校验。
+func debugAuth(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	want := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(strings.TrimSpace(r.Header.Get("Authorization")))
+
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cxio/archives/logs"
+)
+
+func TestDebugLogLevel(t *testing.T) {
+	defer logs.SetLevel(logs.Level())
+	logs.App.SetOutput(io.Discard)
+	logs.SetLevel("info")
+
+	srv := httptest.NewServer(debugAuth("s3cret", debugMux(true)))
+	defer srv.Close()
+
+	tests := []struct {
+		method, body, token string
+		code                int
+		level               string
+	}{
+		{"GET", "", "s3cret", 200, "info"},
+		{"GET", "", "", 401, "info"},
+		{"PUT", "debug", "wrong", 401, "info"},
+		{"PUT", "debug\n", "s3cret", 200, "debug"},
+		{"PUT", "verbose", "s3cret", 400, "debug"},
+		{"POST", "warn", "s3cret", 405, "debug"},
+		{"PUT", "warn", "s3cret", 200, "warning"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+"/debug/loglevel", strings.NewReader(tt.body))
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.code {
+			t.Errorf("%s %q: status %d, want %d (%s)", tt.method, tt.body, resp.StatusCode, tt.code, body)
+		}
+		if tt.code == 200 && strings.TrimSpace(string(body)) != tt.level {
+			t.Errorf("%s %q: body %q, want %s", tt.method, tt.body, body, tt.level)
+		}
+		if got := logs.Level(); got != tt.level {
+			t.Errorf("%s %q: level %s, want %s", tt.method, tt.body, got, tt.level)
+		}
+	}
+}
+
+func TestDebugAuthOpen(t *testing.T) {
+	defer logs.SetLevel(logs.Level())
+	logs.SetLevel("info")
+
+	srv := httptest.NewServer(debugAuth("", debugMux(false)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/vars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("GET /debug/vars without token: %d", resp.StatusCode)
+	}
+	// 无令牌时只读
+	req, _ := http.NewRequest("PUT", srv.URL+"/debug/loglevel", strings.NewReader("debug"))
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed || logs.Level() != "info" {
+		t.Errorf("PUT /debug/loglevel without token: %d, level %s", resp.StatusCode, logs.Level())
+	}
+}
@@ -0,0 +1,103 @@
+// Package disk 监测存储根目录所在磁盘的空间。
+//
+// 使用率超过高水位时，监测器进入满载状态并记录警告，
+// 写入方应据此拒绝新的存储（HTTP 507），而不是在写入中途失败。
+// 使用率回落到高水位之下后自动恢复。
+package disk
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cxio/archives/logs"
+)
+
+// Usage 磁盘空间使用情况（字节）。
+type Usage struct {
+	Total uint64 // 总容量
+	Used  uint64 // 已用空间
+	Free  uint64 // 非特权用户可用的空间
+}
+
+// Percent 返回使用率（0-100）。
+// 与 df 的计算相同，不计入为特权用户保留的空间。
+func (u Usage) Percent() float64 {
+	if u.Used+u.Free == 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Used+u.Free)
+}
+
+// Monitor 磁盘空间监测器。
+type Monitor struct {
+	path string
+	high atomic.Uint32 // 高水位（使用率百分比），0表示不限
+	full atomic.Bool
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewMonitor 创建目标路径的监测器。
+// high 为高水位（使用率百分比），0表示不设限。
+func NewMonitor(path string, high int) *Monitor {
+	m := &Monitor{
+		path: path,
+		stop: make(chan struct{}),
+	}
+	m.high.Store(uint32(high))
+	return m
+}
+
+// SetHigh 设置高水位，可在运行中调整。
+// 新的水位在下次检查时生效。
+func (m *Monitor) SetHigh(high int) {
+	m.high.Store(uint32(high))
+}
+
+// Full 返回磁盘是否已超过高水位。
+func (m *Monitor) Full() bool {
+	return m.full.Load()
+}
+
+// Check 检查一次磁盘空间，更新满载状态。
+// 状态变化时记录日志，持续满载时每次检查都记录警告。
+func (m *Monitor) Check() (Usage, error) {
+	u, err := Stat(m.path)
+	if err != nil {
+		return u, err
+	}
+	high := m.high.Load()
+	full := high > 0 && u.Percent() >= float64(high)
+
+	switch was := m.full.Swap(full); {
+	case full:
+		logs.App.Warnf("disk usage %.1f%% of %s reached the %d%% watermark, new documents are rejected", u.Percent(), m.path, high)
+	case was:
+		logs.App.Infof("disk usage %.1f%% of %s back below the %d%% watermark", u.Percent(), m.path, high)
+	}
+	return u, nil
+}
+
+// Run 按间隔周期性检查，直到 Stop 被调用。
+// 应在独立的 goroutine 中执行。
+func (m *Monitor) Run(interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		if _, err := m.Check(); err != nil {
+			logs.App.Errorf("disk check on %s: %v", m.path, err)
+		}
+		select {
+		case <-tick.C:
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Stop 停止周期检查。
+func (m *Monitor) Stop() {
+	m.once.Do(func() { close(m.stop) })
+}
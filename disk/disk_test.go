@@ -0,0 +1,58 @@
+package disk
+
+import (
+	"io"
+	"testing"
+
+	"github.com/cxio/archives/logs"
+)
+
+func init() {
+	logs.App.SetOutput(io.Discard)
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		u    Usage
+		want float64
+	}{
+		{Usage{}, 0},
+		{Usage{Total: 100, Used: 50, Free: 50}, 50},
+		{Usage{Total: 100, Used: 45, Free: 50}, 47.36842105263158}, // 5 为保留空间
+		{Usage{Total: 100, Used: 95, Free: 0}, 100},
+	}
+	for _, tt := range tests {
+		if got := tt.u.Percent(); got != tt.want {
+			t.Errorf("%+v.Percent() = %v, want %v", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	dir := t.TempDir()
+	u, err := Stat(dir)
+	if err != nil {
+		t.Skipf("Stat: %v", err)
+	}
+	if u.Total == 0 || u.Used+u.Free > u.Total {
+		t.Fatalf("Stat(%s) = %+v", dir, u)
+	}
+	if _, err := NewMonitor(dir+"/missing", 90).Check(); err == nil {
+		t.Error("Check on missing path succeeded")
+	}
+	m := NewMonitor(dir, 0)
+	if _, err := m.Check(); err != nil || m.Full() {
+		t.Fatalf("no watermark: full %v, %v", m.Full(), err)
+	}
+	if u.Percent() < 1 {
+		t.Skipf("disk usage %.2f%% too low to reach a watermark", u.Percent())
+	}
+	m.SetHigh(int(u.Percent()))
+	if m.Check(); !m.Full() {
+		t.Error("usage above watermark, monitor is not full")
+	}
+	m.SetHigh(100)
+	if m.Check(); m.Full() && u.Percent() < 99 {
+		t.Error("usage below watermark, monitor is still full")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build darwin || freebsd
+
+package disk
+
+import "syscall"
+
+// Stat 获取路径所在文件系统的空间使用情况。
+// BSD 系的块计数以 f_bsize 为单位。
+func Stat(path string) (Usage, error) {
+	var st syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bs := uint64(st.Bsize)
+
+	return Usage{
+		Total: uint64(st.Blocks) * bs,
+		Used:  uint64(st.Blocks-st.Bfree) * bs,
+		Free:  uint64(st.Bavail) * bs,
+	}, nil
+}
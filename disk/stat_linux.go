@@ -0,0 +1,21 @@
+package disk
+
+import "syscall"
+
+// Stat 获取路径所在文件系统的空间使用情况。
+// Linux 的块计数以 f_frsize 为单位，f_bsize 仅为建议的读写大小，
+// 两者在部分 NFS、FUSE 等文件系统上并不相同。
+func Stat(path string) (Usage, error) {
+	var st syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bs := uint64(st.Frsize)
+
+	return Usage{
+		Total: st.Blocks * bs,
+		Used:  (st.Blocks - st.Bfree) * bs,
+		Free:  st.Bavail * bs,
+	}, nil
+}
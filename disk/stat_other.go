@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package disk
+
+import "errors"
+
+// Stat 在当前平台上不支持。
+func Stat(string) (Usage, error) {
+	return Usage{}, errors.ErrUnsupported
+}
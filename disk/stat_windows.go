@@ -0,0 +1,20 @@
+package disk
+
+import "golang.org/x/sys/windows"
+
+// Stat 获取路径所在卷的空间使用情况。
+func Stat(path string) (Usage, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return Usage{}, err
+	}
+	var u Usage
+	var free uint64
+
+	if err := windows.GetDiskFreeSpaceEx(p, &u.Free, &u.Total, &free); err != nil {
+		return Usage{}, err
+	}
+	u.Used = u.Total - free
+
+	return u, nil
+}
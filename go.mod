@@ -5,12 +5,16 @@ go 1.23.6
 require (
 	github.com/hjson/hjson-go v3.3.0+incompatible
 	golang.org/x/crypto v0.36.0
+	lukechampine.com/blake3 v1.4.1
 )
 
-require github.com/stretchr/testify v1.8.4 // indirect
+require (
+	github.com/klauspost/cpuid/v2 v2.0.9 // indirect
+	github.com/stretchr/testify v1.8.4 // indirect
+)
 
 require (
 	github.com/gorilla/mux v1.8.1
 	github.com/sirupsen/logrus v1.9.3
-	golang.org/x/sys v0.31.0 // indirect
+	golang.org/x/sys v0.31.0
 )
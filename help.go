@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/cxio/archives/locale"
+)
+
+// 使用示例。
+// 命令中的 {app} 替换为程序名。
+var examples = []struct {
+	cmd  string
+	desc string
+}{
+	{"{app} &", "以默认配置文件启动服务"},
+	{"{app} --config yourconf.hjson &", "以指定的配置文件启动服务，可用于同一主机上的多个实例"},
+}
+
+// 选项条目。
+// 同一用法说明的多个标志视为别名，合并显示。
+type option struct {
+	names []string
+	value string
+	usage string
+	deflt string
+}
+
+// 从标志定义构造选项列表（按标志名称排序）。
+func options(fs *flag.FlagSet) []*option {
+	var list []*option
+	index := make(map[string]*option)
+
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "--" + f.Name
+		if len(f.Name) == 1 {
+			name = "-" + f.Name
+		}
+		if opt, ok := index[f.Usage]; ok {
+			opt.names = append(opt.names, name)
+			return
+		}
+		value, usage := flag.UnquoteUsage(f)
+		opt := &option{names: []string{name}, usage: usage}
+
+		// 布尔标志无需值，也不显示默认值
+		if value != "" {
+			opt.value = value
+			opt.deflt = f.DefValue
+		}
+		index[f.Usage] = opt
+		list = append(list, opt)
+	})
+	return list
+}
+
+// 显示帮助信息。
+// 内容由标志定义和示例生成，按目标语言本地化。
+func showHelpInfo(w io.Writer, lang string) {
+	tr := func(s string) string { return locale.GetText(lang, s) }
+	app := filepath.Base(os.Args[0])
+
+	fmt.Fprintf(w, "%s v%s\n\n", tr("开放存档服务"), Version)
+	fmt.Fprintf(w, "%s\n  %s [%s]\n\n", tr("用法："), app, tr("选项"))
+
+	fmt.Fprintln(w, tr("选项："))
+	opts := options(flag.CommandLine)
+	heads := make([]string, len(opts))
+	width := 0
+
+	for i, opt := range opts {
+		heads[i] = strings.Join(opt.names, ", ")
+		if opt.value != "" {
+			heads[i] += " " + tr(opt.value)
+		}
+		width = max(width, textWidth(heads[i]))
+	}
+	for i, opt := range opts {
+		usage := tr(opt.usage)
+		if opt.deflt != "" {
+			usage += fmt.Sprintf(" (%s%s)", tr("默认："), opt.deflt)
+		}
+		pad := strings.Repeat(" ", width-textWidth(heads[i])+2)
+		fmt.Fprintf(w, "  %s%s%s\n", heads[i], pad, usage)
+	}
+
+	fmt.Fprintf(w, "\n%s\n", tr("示例："))
+	for _, ex := range examples {
+		fmt.Fprintf(w, "  %s\n      %s\n", strings.ReplaceAll(ex.cmd, "{app}", app), tr(ex.desc))
+	}
+}
+
+// 显示版本信息。
+func showVersion(w io.Writer, lang string) {
+	fmt.Fprintf(w, "%s v%s\n", locale.GetText(lang, "开放存档服务"), Version)
+}
+
+// 计算文本的显示宽度。
+// 中日韩等宽字符占两列，以便本地化后的选项列仍能对齐。
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || (r >= 0xff01 && r <= 0xff60) {
+			n++
+		}
+	}
+	return n
+}
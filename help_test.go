@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"--config", 8},
+		{"--config 文件", 13},
+		{"かな", 4},
+		{"한글", 4},
+		{"（全角）", 8},
+	}
+	for _, tt := range tests {
+		if got := textWidth(tt.s); got != tt.want {
+			t.Errorf("textWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var b bool
+	var s string
+	fs.BoolVar(&b, "h", false, "显示帮助信息")
+	fs.BoolVar(&b, "help", false, "显示帮助信息")
+	fs.StringVar(&s, "config", "./config.hjson", "指定配置`文件`路径")
+
+	opts := options(fs)
+	if len(opts) != 2 {
+		t.Fatalf("got %d options, want 2", len(opts))
+	}
+	cfg, help := opts[0], opts[1]
+
+	if strings.Join(cfg.names, ",") != "--config" || cfg.value != "文件" || cfg.usage != "指定配置文件路径" || cfg.deflt != "./config.hjson" {
+		t.Errorf("config option = %+v", *cfg)
+	}
+	if strings.Join(help.names, ",") != "-h,--help" || help.value != "" || help.deflt != "" {
+		t.Errorf("help option = %+v", *help)
+	}
+}
+
+func TestShowHelpInfo(t *testing.T) {
+	var buf bytes.Buffer
+	showHelpInfo(&buf, "en-us")
+	out := buf.String()
+
+	for _, s := range []string{"Open Archives Service", "Usage:", "--config file", "-h, --help", "Check the configuration and exit"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("help output lacks %q:\n%s", s, out)
+		}
+	}
+}
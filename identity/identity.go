@@ -0,0 +1,190 @@
+// Package identity 管理节点的身份密钥和存档回执。
+//
+// 每个节点持有一个持久的 Ed25519 密钥对，成功存储文档后，
+// 节点用私钥签发回执，上传者可凭回执证明文档已被该节点存档。
+package identity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cxio/archives/utils"
+)
+
+// 密钥文件的PEM块类型。
+const pemType = "PRIVATE KEY"
+
+// 回执签名的上下文标识，防止签名被挪用于其它数据。
+const receiptTag = "archives-receipt/1"
+
+// ErrSignature 回执签名无效。
+var ErrSignature = errors.New("invalid receipt signature")
+
+// Identity 节点身份。
+type Identity struct {
+	priv ed25519.PrivateKey
+}
+
+// Load 从密钥文件载入节点身份。
+// 文件不存在时创建新的密钥对并保存（仅属主可读写）。
+func Load(path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return create(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parse(path, data)
+}
+
+// 解析密钥文件的内容。
+func parse(path string, data []byte) (*Identity, error) {
+	blk, _ := pem.Decode(data)
+	if blk == nil || blk.Type != pemType {
+		return nil, fmt.Errorf("bad key file %s", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(blk.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key in %s is not ed25519", path)
+	}
+	return &Identity{priv: priv}, nil
+}
+
+// 创建并保存新的节点密钥。
+// 密钥先完整写入同目录的临时文件，再以硬链接发布：链接不会覆盖已有文件，
+// 因此崩溃不会留下残缺的密钥文件，同时启动的多个实例也只有一个能成功创建，
+// 其余的改为载入已创建的密钥。
+func create(path string) (*Identity, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der})
+
+	tmp, err := writeTemp(filepath.Dir(path), data)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	err = os.Link(tmp, path)
+	if errors.Is(err, os.ErrExist) {
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+		return parse(path, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{priv: priv}, nil
+}
+
+// 写入数据到目录中的临时文件（仅属主可读写），返回其路径。
+// 数据已同步到磁盘。
+func writeTemp(dir string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, ".key-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// PublicKey 返回节点公钥。
+func (id *Identity) PublicKey() ed25519.PublicKey {
+	return id.priv.Public().(ed25519.PublicKey)
+}
+
+// String 返回公钥的十六进制表示。
+func (id *Identity) String() string {
+	return hex.EncodeToString(id.PublicKey())
+}
+
+// Receipt 存档回执。
+type Receipt struct {
+	DocID string `json:"id"`   // 文档ID
+	Year  int    `json:"year"` // 存档年度
+	Size  int64  `json:"size"` // 文档大小
+	Time  int64  `json:"time"` // 存档时间（Unix秒）
+	Node  []byte `json:"node"` // 节点公钥
+	Sig   []byte `json:"sig"`  // 签名
+}
+
+// Issue 签发一个存档回执。
+// 文档ID经 utils.NormalizeID 转换，同一文档的各种写法签发相同的回执。
+func (id *Identity) Issue(docID string, year int, size int64, t time.Time) (*Receipt, error) {
+	docID, err := utils.NormalizeID(docID)
+	if err != nil {
+		return nil, err
+	}
+	r := &Receipt{
+		DocID: docID,
+		Year:  year,
+		Size:  size,
+		Time:  t.Unix(),
+		Node:  id.PublicKey(),
+	}
+	r.Sig = ed25519.Sign(id.priv, r.message())
+
+	return r, nil
+}
+
+// Verify 验证回执签名。
+// 仅证明回执由 Node 公钥的持有者签发，调用者应自行确认该公钥可信。
+func (r *Receipt) Verify() error {
+	if len(r.Node) != ed25519.PublicKeySize {
+		return ErrSignature
+	}
+	if !ed25519.Verify(ed25519.PublicKey(r.Node), r.message(), r.Sig) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// 构造签名的消息体。
+// 格式：标识、文档ID、年度、大小、时间、公钥，换行分隔。
+func (r *Receipt) message() []byte {
+	fields := []string{
+		receiptTag,
+		r.DocID,
+		strconv.Itoa(r.Year),
+		strconv.FormatInt(r.Size, 10),
+		strconv.FormatInt(r.Time, 10),
+		hex.EncodeToString(r.Node),
+	}
+	return []byte(strings.Join(fields, "\n"))
+}
@@ -0,0 +1,94 @@
+// Package locale 提供界面消息的本地化。
+//
+// 消息文件为本目录下的 JSON 文件，文件名为语言和地区的名称（如 zh-cn.json），
+// 内容为原文到译文的映射。原文即源码中的消息字符串，作为键不应重复。
+// 若目标语言与原文相同，无需翻译，条目可省略。
+package locale
+
+import (
+	"embed"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed *.json
+var files embed.FS
+
+var (
+	once  sync.Once
+	texts map[string]map[string]string // 语言 -> 原文 -> 译文
+)
+
+// 载入全部消息文件。
+// 格式错误的文件被忽略，相应语言将回退到原文。
+func load() {
+	texts = make(map[string]map[string]string)
+	list, _ := files.ReadDir(".")
+
+	for _, f := range list {
+		data, err := files.ReadFile(f.Name())
+		if err != nil {
+			continue
+		}
+		m := make(map[string]string)
+
+		if json.Unmarshal(data, &m) == nil {
+			texts[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = m
+		}
+	}
+}
+
+// GetText 获取消息在目标语言中的翻译文本。
+// lang 为语言和地区名称，如 en-us。找不到译文时返回原文。
+// 若没有地区完全匹配的消息文件，采用同语种的其它地区。
+func GetText(lang, msg string) string {
+	once.Do(load)
+
+	if m, ok := texts[match(lang)]; ok {
+		if s := m[msg]; s != "" {
+			return s
+		}
+	}
+	return msg
+}
+
+// Lang 从环境变量检测用户的界面语言。
+// 依次检查 LC_ALL、LC_MESSAGES 和 LANG，如 zh_CN.UTF-8 转为 zh-cn。
+// 未设置或为 C/POSIX 时返回空串。
+func Lang() string {
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(k)
+		if v == "" {
+			continue
+		}
+		if i := strings.IndexAny(v, ".@"); i >= 0 {
+			v = v[:i]
+		}
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return strings.ToLower(strings.ReplaceAll(v, "_", "-"))
+	}
+	return ""
+}
+
+// 匹配可用的消息文件名。
+func match(lang string) string {
+	lang = strings.ToLower(lang)
+	if _, ok := texts[lang]; ok || lang == "" {
+		return lang
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	found := lang
+
+	// 多个地区时取名称最小者，保证结果确定
+	for name := range texts {
+		if n, _, _ := strings.Cut(name, "-"); n == base && (found == lang || name < found) {
+			found = name
+		}
+	}
+	return found
+}
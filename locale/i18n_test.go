@@ -0,0 +1,68 @@
+package locale
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	once.Do(load)
+	texts["en-gb"] = map[string]string{}
+	defer delete(texts, "en-gb")
+
+	tests := []struct {
+		lang, want string
+	}{
+		{"en-us", "en-us"},
+		{"EN-US", "en-us"},
+		{"en-gb", "en-gb"},
+		{"en-au", "en-gb"}, // 同语种取名称最小者
+		{"en", "en-gb"},
+		{"zh-tw", "zh-cn"},
+		{"fr-fr", "fr-fr"}, // 无匹配，原样返回
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := match(tt.lang); got != tt.want {
+			t.Errorf("match(%q) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestGetText(t *testing.T) {
+	tests := []struct {
+		lang, msg, want string
+	}{
+		{"en-us", "用法：", "Usage:"},
+		{"en-gb", "用法：", "Usage:"},
+		{"zh-cn", "用法：", "用法："},
+		{"fr-fr", "用法：", "用法："},
+		{"", "用法：", "用法："},
+		{"en-us", "未翻译的消息", "未翻译的消息"},
+	}
+	for _, tt := range tests {
+		if got := GetText(tt.lang, tt.msg); got != tt.want {
+			t.Errorf("GetText(%q, %q) = %q, want %q", tt.lang, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestLang(t *testing.T) {
+	tests := []struct {
+		all, messages, lang string
+		want                string
+	}{
+		{"", "", "zh_CN.UTF-8", "zh-cn"},
+		{"", "en_US", "zh_CN.UTF-8", "en-us"},
+		{"en_GB.UTF-8@euro", "", "zh_CN", "en-gb"},
+		{"C", "", "zh_CN", ""},
+		{"", "", "POSIX", ""},
+		{"", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.all)
+		t.Setenv("LC_MESSAGES", tt.messages)
+		t.Setenv("LANG", tt.lang)
+
+		if got := Lang(); got != tt.want {
+			t.Errorf("Lang() with %q/%q/%q = %q, want %q", tt.all, tt.messages, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//go:build linux
+
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// systemd-journal 的原生协议套接字。
+const journalSocket = "/run/systemd/journal/socket"
+
+// 输出到 systemd-journal 的钩子。
+// 采用原生协议，日志字段作为独立的 journal 字段写入，便于 journalctl 过滤。
+type journalHook struct {
+	conn *net.UnixConn
+	name string
+}
+
+// 创建 journal 钩子。
+func newJournalHook(name string) (logrus.Hook, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journalHook{conn: conn, name: name}, nil
+}
+
+func (h *journalHook) Close() error {
+	return h.conn.Close()
+}
+
+func (h *journalHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *journalHook) Fire(e *logrus.Entry) error {
+	var buf bytes.Buffer
+
+	journalField(&buf, "MESSAGE", e.Message)
+	journalField(&buf, "PRIORITY", strconv.Itoa(priority(e.Level)))
+	journalField(&buf, "SYSLOG_IDENTIFIER", sysIdent)
+	journalField(&buf, "ARCHIVES_LOGGER", h.name)
+
+	for k, v := range e.Data {
+		if key := journalKey(k); key != "" {
+			journalField(&buf, key, fmt.Sprint(v))
+		}
+	}
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+// 写入一个 journal 字段。
+// 多行的值采用二进制格式：名称、换行、64位小端长度、值、换行。
+func journalField(buf *bytes.Buffer, key, val string) {
+	buf.WriteString(key)
+
+	if !strings.ContainsRune(val, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(val)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(val)))
+	buf.WriteString(val)
+	buf.WriteByte('\n')
+}
+
+// 转换日志字段名为 journal 字段名。
+// 仅含大写字母、数字和下划线，加前缀以免与 journal 自身的字段冲突。
+func journalKey(k string) string {
+	if k == "" {
+		return ""
+	}
+	b := []byte(strings.ToUpper(k))
+	for i, c := range b {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	return "ARCHIVES_" + string(b)
+}
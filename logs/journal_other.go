@@ -0,0 +1,13 @@
+//go:build !linux
+
+package logs
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newJournalHook(string) (logrus.Hook, error) {
+	return nil, errors.New("journal output is only supported on linux")
+}
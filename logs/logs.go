@@ -0,0 +1,197 @@
+// Package logs 提供全局的日志记录器。
+//
+//   - App:  程序普通日志，包括错误、警告和普通信息（arch.log）。
+//   - Dev:  开发阶段需要的详细信息（debug.log）。
+//   - Data: 数据存储和请求相关的信息（data.log）。
+//
+// 日志默认写入日志根目录下的文件，也可输出到系统日志（syslog 或 systemd-journal）。
+// 初始化之前，日志输出到标准错误。
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 文本日志的时间格式。
+// JSON 日志采用 RFC3339，带时区信息。
+const timeLayout = "2006-01-02 15:04:05"
+
+// 日志格式名称。
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// 日志输出目标。
+const (
+	OutputFile    = "file"    // 日志根目录下的文件
+	OutputSyslog  = "syslog"  // 本机 syslog 服务
+	OutputJournal = "journal" // systemd-journal
+)
+
+// 全局日志记录器。
+var (
+	App  = logrus.New()
+	Dev  = logrus.New()
+	Data = logrus.New()
+)
+
+// 日志记录器的名称和文件名。
+var targets = []struct {
+	log  *logrus.Logger
+	name string
+	file string
+}{
+	{App, "app", "arch.log"},
+	{Dev, "dev", "debug.log"},
+	{Data, "data", "data.log"},
+}
+
+// Options 日志初始化选项。
+type Options struct {
+	Root   string // 日志存储根路径，仅用于文件输出
+	Level  string // 日志级别
+	Format string // 输出格式：text, json
+	Output string // 输出目标：file, syslog, journal
+}
+
+// 旧输出的延迟关闭时长。
+// logrus 在自身锁之外调用钩子、写出日志，替换时可能仍有条目正在写入旧的输出，
+// 等待其写完后再关闭。
+const closeDelay = 5 * time.Second
+
+// 当前输出占用的资源（文件、系统日志连接），重新初始化时关闭。
+var (
+	mu      sync.Mutex
+	closers []io.Closer
+)
+
+// InitLogs 初始化日志记录器。
+// 可重复调用以应用新的选项：全部输出创建成功后才替换，失败时保持原状。
+func InitLogs(opt Options) error {
+	lv, err := logrus.ParseLevel(opt.Level)
+	if err != nil {
+		return err
+	}
+	// 系统日志自带时间戳，消息中不再重复
+	fm, err := formatter(opt.Format, opt.Output == OutputFile || opt.Output == "")
+	if err != nil {
+		return err
+	}
+	outs := make([]io.Writer, len(targets))
+	hooks := make([]logrus.LevelHooks, len(targets))
+	var opened []io.Closer
+
+	for i, t := range targets {
+		out, hook, err := openOutput(opt, t.name, t.file, fm)
+		if err != nil {
+			closeAll(opened)
+			return err
+		}
+		hooks[i] = make(logrus.LevelHooks)
+		outs[i] = io.Discard
+
+		if hook != nil {
+			hooks[i].Add(hook)
+			opened = append(opened, hook.(io.Closer))
+		}
+		if out != nil {
+			outs[i] = out
+			opened = append(opened, out)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, t := range targets {
+		t.log.ReplaceHooks(hooks[i])
+		t.log.SetOutput(outs[i])
+		t.log.SetFormatter(fm)
+		t.log.SetLevel(lv)
+	}
+	old := closers
+	closers = opened
+
+	if len(old) > 0 {
+		time.AfterFunc(closeDelay, func() { closeAll(old) })
+	}
+
+	return nil
+}
+
+// 创建日志记录器的输出。
+// 文件输出返回文件，系统日志返回钩子（同时实现 io.Closer）。
+func openOutput(opt Options, name, file string, fm logrus.Formatter) (*os.File, logrus.Hook, error) {
+	switch opt.Output {
+	case OutputFile, "":
+		f, err := openFile(opt.Root, file)
+		return f, nil, err
+	case OutputSyslog:
+		h, err := newSyslogHook(name, fm)
+		return nil, h, err
+	case OutputJournal:
+		h, err := newJournalHook(name)
+		return nil, h, err
+	}
+	return nil, nil, fmt.Errorf("unknown log output: %s", opt.Output)
+}
+
+// 关闭全部资源。
+func closeAll(list []io.Closer) {
+	for _, c := range list {
+		c.Close()
+	}
+}
+
+// 打开日志文件（追加）。
+func openFile(root, name string) (*os.File, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(root, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+// 创建日志格式器。
+// JSON 格式便于 Loki/ELK 等日志系统采集。stamp 为是否输出时间戳。
+func formatter(format string, stamp bool) (logrus.Formatter, error) {
+	switch format {
+	case FormatText, "":
+		return &logrus.TextFormatter{
+			FullTimestamp:    true,
+			TimestampFormat:  timeLayout,
+			DisableTimestamp: !stamp,
+			DisableColors:    true,
+		}, nil
+	case FormatJSON:
+		return &logrus.JSONFormatter{
+			TimestampFormat:  time.RFC3339Nano,
+			DisableTimestamp: !stamp,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown log format: %s", format)
+}
+
+// SetLevel 设置全部日志记录器的级别。
+// 可在运行时调用，无需重新初始化。
+func SetLevel(level string) error {
+	lv, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		t.log.SetLevel(lv)
+	}
+	return nil
+}
+
+// Level 返回当前的日志级别。
+func Level() string {
+	return App.GetLevel().String()
+}
@@ -0,0 +1,71 @@
+package logs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitLogs(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := InitLogs(Options{Root: dir, Level: "debug", Format: FormatJSON}); err != nil {
+		t.Fatalf("InitLogs(json): %v", err)
+	}
+	App.Info("json entry")
+	Data.Debug("data entry")
+
+	var rec map[string]string
+	data, _ := os.ReadFile(filepath.Join(dir, "arch.log"))
+	if err := json.Unmarshal(data, &rec); err != nil || rec["msg"] != "json entry" || rec["level"] != "info" || rec["time"] == "" {
+		t.Errorf("arch.log = %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "data.log")); !strings.Contains(string(data), "data entry") {
+		t.Errorf("data.log = %q", data)
+	}
+
+	// 重新初始化：切换格式和级别
+	if err := InitLogs(Options{Root: dir, Level: "warn", Format: FormatText}); err != nil {
+		t.Fatalf("InitLogs(text): %v", err)
+	}
+	if Level() != "warning" {
+		t.Errorf("Level() = %s, want warning", Level())
+	}
+	App.Info("hidden")
+	App.Warn("text entry")
+
+	data, _ = os.ReadFile(filepath.Join(dir, "arch.log"))
+	if s := string(data); strings.Contains(s, "hidden") || !strings.Contains(s, `level=warning msg="text entry"`) {
+		t.Errorf("arch.log = %q", s)
+	}
+}
+
+func TestInitLogsErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := InitLogs(Options{Root: dir, Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []Options{
+		{Root: dir, Level: "verbose"},
+		{Root: dir, Level: "info", Format: "xml"},
+		{Root: dir, Level: "info", Output: "stdout"},
+		{Root: filepath.Join(dir, "arch.log", "sub"), Level: "debug"},
+	}
+	for _, opt := range tests {
+		if err := InitLogs(opt); err == nil {
+			t.Errorf("InitLogs(%+v) succeeded", opt)
+		}
+	}
+	// 失败时保持原状
+	if Level() != "info" {
+		t.Errorf("Level() = %s after failed InitLogs, want info", Level())
+	}
+	if err := SetLevel("debug"); err != nil || Level() != "debug" {
+		t.Errorf("SetLevel(debug) = %v, level %s", err, Level())
+	}
+	if err := SetLevel("loud"); err == nil {
+		t.Error("SetLevel(loud) succeeded")
+	}
+}
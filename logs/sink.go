@@ -0,0 +1,23 @@
+package logs
+
+import "github.com/sirupsen/logrus"
+
+// 系统日志的程序标识。
+const sysIdent = "archives"
+
+// 转换日志级别为 syslog 优先级（RFC 5424）。
+func priority(lv logrus.Level) int {
+	switch lv {
+	case logrus.PanicLevel:
+		return 0 // emerg
+	case logrus.FatalLevel:
+		return 2 // crit
+	case logrus.ErrorLevel:
+		return 3 // err
+	case logrus.WarnLevel:
+		return 4 // warning
+	case logrus.InfoLevel:
+		return 6 // info
+	}
+	return 7 // debug
+}
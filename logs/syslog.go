@@ -0,0 +1,56 @@
+//go:build !windows && !plan9
+
+package logs
+
+import (
+	"log/syslog"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 输出到本机 syslog 的钩子。
+type syslogHook struct {
+	w  *syslog.Writer
+	fm logrus.Formatter
+}
+
+// 创建 syslog 钩子。
+// 标识为 archives/名称，如 archives/app。
+func newSyslogHook(name string, fm logrus.Formatter) (logrus.Hook, error) {
+	w, err := syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, sysIdent+"/"+name)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogHook{w: w, fm: fm}, nil
+}
+
+func (h *syslogHook) Close() error {
+	return h.w.Close()
+}
+
+func (h *syslogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *syslogHook) Fire(e *logrus.Entry) error {
+	buf, err := h.fm.Format(e)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(string(buf), "\n")
+
+	switch priority(e.Level) {
+	case 0:
+		return h.w.Emerg(msg)
+	case 2:
+		return h.w.Crit(msg)
+	case 3:
+		return h.w.Err(msg)
+	case 4:
+		return h.w.Warning(msg)
+	case 6:
+		return h.w.Info(msg)
+	}
+	return h.w.Debug(msg)
+}
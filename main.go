@@ -1,8 +1,241 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cxio/archives/config"
+	"github.com/cxio/archives/disk"
+	"github.com/cxio/archives/identity"
+	"github.com/cxio/archives/locale"
+	"github.com/cxio/archives/logs"
+	"github.com/cxio/archives/systemd"
+	"github.com/cxio/archives/utils"
+)
+
+// Version 程序版本。
+const Version = "0.1.0"
+
+// 磁盘空间的检查间隔。
+const diskInterval = time.Minute
+
+// 数据磁盘的空间监测器。
+var monitor *disk.Monitor
+
+// 命令行参数。
+var (
+	help       bool
+	version    bool
+	check      bool
+	configFile string
+)
+
+func init() {
+	flag.BoolVar(&help, "h", false, "显示帮助信息")
+	flag.BoolVar(&help, "help", false, "显示帮助信息")
+	flag.BoolVar(&version, "v", false, "显示版本信息")
+	flag.BoolVar(&version, "version", false, "显示版本信息")
+	flag.StringVar(&configFile, "config", defaultConfig(), "指定配置`文件`路径")
+	flag.BoolVar(&check, "check", false, "检查配置的有效性后退出")
+}
+
+// 默认的配置文件路径。
+// 可由环境变量 ARCHIVES_CONFIG 指定，命令行参数优先。
+func defaultConfig() string {
+	if p := os.Getenv(config.EnvPrefix + "CONFIG"); p != "" {
+		return p
+	}
+	return "./config.hjson"
+}
+
+// 确定采用的配置文件（在解析命令行之后调用）。
+// 未明确指定且默认的配置文件不存在时返回空串，此时仅采用默认值和环境变量。
+// 明确指定的文件不存在则为错误，在载入时报告。
+func configPath() string {
+	explicit := os.Getenv(config.EnvPrefix+"CONFIG") != ""
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			explicit = true
+		}
+	})
+	if _, err := os.Stat(configFile); !explicit && errors.Is(err, os.ErrNotExist) {
+		return ""
+	}
+	return configFile
+}
+
+// 配置来源的显示名称。
+func configName(lang string) string {
+	if configFile == "" {
+		return locale.GetText(lang, "（无配置文件）")
+	}
+	return configFile
+}
 
 func main() {
-	// This is a comment
-	fmt.Println("Hello, World!")
+	lang := locale.Lang()
+	flag.Usage = func() { showHelpInfo(flag.CommandLine.Output(), lang) }
+	flag.Parse()
+	configFile = configPath()
+
+	if help {
+		showHelpInfo(os.Stdout, lang)
+		return
+	}
+	if version {
+		showVersion(os.Stdout, lang)
+		return
+	}
+	if check {
+		os.Exit(checkConfig(os.Stdout, lang))
+	}
+	cfg, err := config.Load(configFile)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s:\n%v\n", locale.GetText(lang, "配置无效："), configName(lang), err)
+		os.Exit(1)
+	}
+	if err := logs.InitLogs(logOptions(cfg)); err != nil {
+		fmt.Fprintf(os.Stderr, "init logs: %v\n", err)
+		os.Exit(1)
+	}
+	if err := utils.SetDefault(cfg.HashAlgo); err != nil {
+		logs.App.Fatalf("set hash algorithm: %v", err)
+	}
+	id, err := identity.Load(cfg.KeyFile)
+	if err != nil {
+		logs.App.Fatalf("load node identity: %v", err)
+	}
+	logs.App.Infof("node identity %s", id)
+
+	if err := os.MkdirAll(cfg.DataRoot, 0o755); err != nil {
+		logs.App.Fatalf("create data root: %v", err)
+	}
+	monitor = disk.NewMonitor(cfg.DataRoot, cfg.DiskHighWatermark)
+	go monitor.Run(diskInterval)
+	defer monitor.Stop()
+
+	if srv := startDebug(cfg.DebugAddr, cfg.DebugToken); srv != nil {
+		defer srv.Close()
+	}
+	wd, err := systemd.WatchdogInterval()
+	if err != nil {
+		logs.App.Errorf("systemd watchdog: %v", err)
+	}
+	logs.App.Infof("archives v%s started", Version)
+	sdNotify(systemd.Status(systemd.Ready, "running"))
+
+	waitSignals(cfg, wd/2)
+	sdNotify(systemd.Stopping)
+}
+
+// 向 systemd 发送通知，失败时记录错误。
+// 未由 systemd 管理时无操作。
+func sdNotify(state string) {
+	if _, err := systemd.Notify(state); err != nil {
+		logs.App.Errorf("systemd notify: %v", err)
+	}
+}
+
+// 检查配置文件，输出结果。
+// 返回进程退出码：有效为0，否则为1。
+func checkConfig(w io.Writer, lang string) int {
+	cfg, err := config.Load(configFile)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%s %s:\n", locale.GetText(lang, "配置无效："), configName(lang))
+
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(w, "  - %s\n", line)
+		}
+		return 1
+	}
+	fmt.Fprintf(w, "%s %s\n", locale.GetText(lang, "配置有效："), configName(lang))
+	return 0
+}
+
+// 从配置构造日志选项。
+func logOptions(cfg *config.Config) logs.Options {
+	return logs.Options{
+		Root:   cfg.LogRoot,
+		Level:  cfg.LogLevel,
+		Format: cfg.LogFormat,
+		Output: cfg.LogOutput,
+	}
+}
+
+// 切换调试日志。
+// 当前为 debug 时恢复到配置的级别，否则切换到 debug。
+// 级别变更以警告级别记录，确保在任何级别下都可见。
+func toggleDebug(cfg *config.Config) {
+	if logs.Level() == "debug" {
+		logs.SetLevel(cfg.LogLevel)
+	} else {
+		logs.SetLevel("debug")
+	}
+	logs.App.Warnf("log level switched to %s", logs.Level())
+}
+
+// 重新载入配置文件。
+// 日志、哈希算法和磁盘水位的配置即时生效，端口、数据根目录、密钥文件和调试服务的变更需重启服务。
+// 返回生效的配置，载入或应用失败时保持原配置。
+func reloadConfig(cur *config.Config) *config.Config {
+	cfg, err := config.Load(configFile)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		logs.App.Errorf("reload config: %v", err)
+		return cur
+	}
+	if *cfg == *cur {
+		logs.App.Info("config reloaded, nothing changed")
+		return cur
+	}
+	if logOptions(cfg) != logOptions(cur) {
+		if err := logs.InitLogs(logOptions(cfg)); err != nil {
+			logs.App.Errorf("reload logs: %v", err)
+			return cur
+		}
+	}
+	if cfg.HashAlgo != cur.HashAlgo {
+		// 已校验为已注册的算法
+		utils.SetDefault(cfg.HashAlgo)
+		logs.App.Warnf("hash_algo switched to %s for new documents", cfg.HashAlgo)
+	}
+	monitor.SetHigh(cfg.DiskHighWatermark)
+
+	// 需重启的条目保持运行中的值
+	if cfg.DepotPort != cur.DepotPort {
+		logs.App.Warnf("depot_port changed to %d, restart required", cfg.DepotPort)
+		cfg.DepotPort = cur.DepotPort
+	}
+	if cfg.DataRoot != cur.DataRoot {
+		logs.App.Warnf("data_root changed to %s, restart required", cfg.DataRoot)
+		cfg.DataRoot = cur.DataRoot
+	}
+	if cfg.KeyFile != cur.KeyFile {
+		logs.App.Warnf("key_file changed to %s, restart required", cfg.KeyFile)
+		cfg.KeyFile = cur.KeyFile
+	}
+	if cfg.DebugAddr != cur.DebugAddr || cfg.DebugToken != cur.DebugToken {
+		logs.App.Warnf("debug_addr/debug_token changed, restart required")
+		cfg.DebugAddr, cfg.DebugToken = cur.DebugAddr, cur.DebugToken
+	}
+	if configFile == "" {
+		logs.App.Warn("config reloaded from environment")
+	} else {
+		logs.App.Warnf("config reloaded from %s", configFile)
+	}
+	return cfg
 }
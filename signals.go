@@ -0,0 +1,52 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cxio/archives/config"
+	"github.com/cxio/archives/logs"
+	"github.com/cxio/archives/systemd"
+)
+
+// 处理控制信号，直到收到退出信号。
+//   - SIGUSR1: 切换调试日志（debug 与配置的级别之间）
+//   - SIGHUP:  重新载入配置文件
+//   - SIGINT/SIGTERM: 退出
+//
+// beat 非0时，以此间隔向 systemd 发送看门狗心跳。心跳与信号在同一循环中处理，
+// 信号处理阻塞时心跳随之停止，由 systemd 重启服务。
+func waitSignals(cfg *config.Config, beat time.Duration) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(ch)
+
+	var tick <-chan time.Time
+	if beat > 0 {
+		t := time.NewTicker(beat)
+		defer t.Stop()
+		tick = t.C
+	}
+	for {
+		select {
+		case <-tick:
+			sdNotify(systemd.Ping)
+		case sig := <-ch:
+			switch sig {
+			case syscall.SIGUSR1:
+				toggleDebug(cfg)
+			case syscall.SIGHUP:
+				sdNotify(systemd.Reloading())
+				cfg = reloadConfig(cfg)
+				sdNotify(systemd.Status(systemd.Ready, "running"))
+			default:
+				logs.App.Infof("received %s, exiting", sig)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cxio/archives/config"
+	"github.com/cxio/archives/logs"
+)
+
+// 等待退出信号。
+// Windows 下没有 SIGUSR1/SIGHUP，配置不可在运行时重新载入，也没有 systemd 看门狗。
+func waitSignals(*config.Config, time.Duration) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	defer signal.Stop(ch)
+
+	logs.App.Infof("received %s, exiting", <-ch)
+}
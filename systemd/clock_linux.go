@@ -0,0 +1,13 @@
+package systemd
+
+import "golang.org/x/sys/unix"
+
+// 返回 CLOCK_MONOTONIC 的当前值（微秒），获取失败时返回0。
+func monotonicUsec() uint64 {
+	var ts unix.Timespec
+
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return 0
+	}
+	return uint64(ts.Sec)*1e6 + uint64(ts.Nsec)/1e3
+}
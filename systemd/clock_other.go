@@ -0,0 +1,8 @@
+//go:build !linux
+
+package systemd
+
+// systemd 仅用于 Linux，其它平台不提供单调时钟的时间戳。
+func monotonicUsec() uint64 {
+	return 0
+}
@@ -0,0 +1,79 @@
+// Package systemd 实现与 systemd 的集成：就绪通知和看门狗。
+//
+// 仅在由 systemd 启动（设置了 NOTIFY_SOCKET）时生效，否则各操作为空操作，
+// 因此调用方无需区分运行环境。对应的服务单元应配置 Type=notify，
+// 需要看门狗时再配置 WatchdogSec=。
+//
+// 看门狗的心跳（Ping）应由服务的主循环发送，而非独立的 goroutine，
+// 这样主循环阻塞时心跳随之停止，systemd 才能发现并重启服务。
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 通知状态。
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Ping     = "WATCHDOG=1"
+)
+
+// Notify 向 systemd 发送状态通知。
+// 未由 systemd 管理时返回 false 和 nil 错误。
+func Notify(state string) (bool, error) {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return false, nil
+	}
+	// @ 开头为 Linux 抽象套接字
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Status 构造附带状态说明的通知。
+func Status(state, msg string) string {
+	return state + "\nSTATUS=" + msg
+}
+
+// Reloading 构造重新载入配置的通知。
+// 附带单调时钟的时间戳，以兼容 Type=notify-reload。
+func Reloading() string {
+	if usec := monotonicUsec(); usec > 0 {
+		return "RELOADING=1\nMONOTONIC_USEC=" + strconv.FormatUint(usec, 10)
+	}
+	return "RELOADING=1"
+}
+
+// WatchdogInterval 返回看门狗的超时时长。
+// 未启用看门狗，或看门狗不针对本进程时返回0。
+// 心跳的间隔通常取超时的一半。
+func WatchdogInterval() (time.Duration, error) {
+	s := os.Getenv("WATCHDOG_USEC")
+	if s == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+	usec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, fmt.Errorf("bad WATCHDOG_USEC: %q", s)
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
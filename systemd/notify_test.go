@@ -0,0 +1,76 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if ok, err := Notify(Ready); ok || err != nil {
+		t.Errorf("Notify without socket = %v, %v", ok, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	msg := Status(Ready, "running")
+	if ok, err := Notify(msg); !ok || err != nil {
+		t.Fatalf("Notify = %v, %v", ok, err)
+	}
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "READY=1\nSTATUS=running" {
+		t.Errorf("received %q, %v", buf[:n], err)
+	}
+}
+
+func TestReloading(t *testing.T) {
+	s := Reloading()
+	if !strings.HasPrefix(s, "RELOADING=1") {
+		t.Errorf("Reloading() = %q", s)
+	}
+	if _, usec, ok := strings.Cut(s, "\nMONOTONIC_USEC="); ok {
+		if n, err := strconv.ParseUint(usec, 10, 64); err != nil || n == 0 {
+			t.Errorf("bad MONOTONIC_USEC %q", usec)
+		}
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		usec, pid string
+		want      time.Duration
+		err       bool
+	}{
+		{"", "", 0, false},
+		{"30000000", "", 30 * time.Second, false},
+		{"30000000", pid, 30 * time.Second, false},
+		{"30000000", "1", 0, false}, // 针对其它进程
+		{"0", "", 0, true},
+		{"-1", "", 0, true},
+		{"30s", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Setenv("WATCHDOG_USEC", tt.usec)
+		t.Setenv("WATCHDOG_PID", tt.pid)
+
+		d, err := WatchdogInterval()
+		if d != tt.want || (err != nil) != tt.err {
+			t.Errorf("WATCHDOG_USEC=%q WATCHDOG_PID=%q: got %v, %v", tt.usec, tt.pid, d, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// Base58 字符表（比特币风格）。
+const b58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// CID 相关常量。
+const (
+	cidV1      = 0x01 // CID 版本1
+	cidRaw     = 0x55 // 原始二进制编解码码
+	multibaseZ = 'z'  // base58btc 的 multibase 前缀
+)
+
+var (
+	b58Radix = big.NewInt(58)
+	b58Index [128]int8
+)
+
+func init() {
+	for i := range b58Index {
+		b58Index[i] = -1
+	}
+	for i := 0; i < len(b58Alphabet); i++ {
+		b58Index[b58Alphabet[i]] = int8(i)
+	}
+}
+
+// Base58Encode 编码字节序列为 Base58 字符串。
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	n := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	buf := make([]byte, 0, len(data)*138/100+1)
+
+	for n.Sign() > 0 {
+		n.DivMod(n, b58Radix, mod)
+		buf = append(buf, b58Alphabet[mod.Int64()])
+	}
+	for ; zeros > 0; zeros-- {
+		buf = append(buf, b58Alphabet[0])
+	}
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf)
+}
+
+// Base58Decode 解码 Base58 字符串。
+func Base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	zeros := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 || b58Index[c] < 0 {
+			return nil, fmt.Errorf("%w: bad base58 char %q", ErrBadID, c)
+		}
+		if n.Sign() == 0 && c == b58Alphabet[0] {
+			zeros++
+			continue
+		}
+		n.Mul(n, b58Radix)
+		n.Add(n, big.NewInt(int64(b58Index[c])))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// IDToBase58 转换十六进制文档ID为 Base58 形式。
+// 结果为 multihash 的 Base58 编码，无前缀的ID会先补齐前缀。
+// 仅 sha2-256 的结果与 CIDv0 相同。
+func IDToBase58(id string) (string, error) {
+	a, sum, err := ParseID(id)
+	if err != nil {
+		return "", err
+	}
+	raw, _ := hex.DecodeString(EncodeID(a.Code, sum))
+	return Base58Encode(raw), nil
+}
+
+// IDToCID 转换十六进制文档ID为 CIDv1（raw 编解码，base58btc）。
+// 无前缀的ID会补齐 SHA3-256 前缀，因为 CID 要求自描述的 multihash。
+func IDToCID(id string) (string, error) {
+	a, sum, err := ParseID(id)
+	if err != nil {
+		return "", err
+	}
+	buf := []byte{cidV1, cidRaw, a.Code, byte(len(sum))}
+
+	return string(multibaseZ) + Base58Encode(append(buf, sum...)), nil
+}
+
+// NormalizeID 转换外部传入的文档ID为内部的十六进制形式。
+// 接受十六进制ID、Base58 编码的ID（含CIDv0），以及 base58btc 的 CIDv1。
+// 返回的ID已通过 ParseID 校验，且总是带前缀的形式（同 HashID），
+// 因此同一文档的各种写法都对应同一个内部ID。
+func NormalizeID(s string) (string, error) {
+	a, sum, err := ParseID(s)
+	if err != nil {
+		raw, err := decodeCID(s)
+		if err != nil {
+			return "", err
+		}
+		if a, sum, err = ParseID(hex.EncodeToString(raw)); err != nil {
+			return "", err
+		}
+	}
+	return EncodeID(a.Code, sum), nil
+}
+
+// 解码 Base58 形式的ID，返回其原始字节（multihash 或无前缀摘要）。
+// CIDv1 只接受 raw 编解码，其它编解码（如 dag-pb）的内容并非文档本身。
+func decodeCID(s string) ([]byte, error) {
+	if len(s) > 1 && s[0] == multibaseZ {
+		raw, err := Base58Decode(s[1:])
+		if err == nil && len(raw) > 0 && raw[0] == cidV1 {
+			codec, n := binary.Uvarint(raw[1:])
+			if n <= 0 || codec != cidRaw {
+				return nil, fmt.Errorf("%w: cid codec is not raw", ErrBadID)
+			}
+			return raw[1+n:], nil
+		}
+	}
+	return Base58Decode(s)
+}
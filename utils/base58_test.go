@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		data string // 十六进制
+		want string
+	}{
+		{"", ""},
+		{"00", "1"},
+		{"000001", "112"},
+		{hex.EncodeToString([]byte("Hello World!")), "2NEpo7TZRRrLZSi2U"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		if got := Base58Encode(data); got != tt.want {
+			t.Errorf("Base58Encode(%s) = %s, want %s", tt.data, got, tt.want)
+		}
+		back, err := Base58Decode(tt.want)
+		if err != nil || !bytes.Equal(back, data) {
+			t.Errorf("Base58Decode(%s) = %x, %v", tt.want, back, err)
+		}
+	}
+	for _, s := range []string{"0", "O", "I", "l", "abc+", "中"} {
+		if _, err := Base58Decode(s); !errors.Is(err, ErrBadID) {
+			t.Errorf("Base58Decode(%q) = %v, want ErrBadID", s, err)
+		}
+	}
+}
+
+func TestCIDKnown(t *testing.T) {
+	// sha2-256 的 multihash Base58 即 CIDv0
+	id := EncodeID(SHA2_256, mustHex("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+	b58, err := IDToBase58(id)
+	if err != nil || b58 != "QmdfTbBqBPQ7VNxZEYEj14VmRuZBkqFbiwReogJgS1zR1n" {
+		t.Errorf("IDToBase58 = %s, %v", b58, err)
+	}
+	cid, err := IDToCID(id)
+	if err != nil || cid != "zb2rhmy65F3REf8SZp7De11gxtECBGgUKaLdiDj7MCGCHxbDW" {
+		t.Errorf("IDToCID = %s, %v", cid, err)
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	legacy := hex.EncodeToString(HashSHA3([]byte("archives")))
+	want := "1620" + legacy
+
+	ids := map[string]string{
+		"sha3-256": want,
+		"sha2-256": EncodeID(SHA2_256, mustHex(emptySums[SHA2_256])),
+		"blake3":   EncodeID(BLAKE3, mustHex(emptySums[BLAKE3])),
+	}
+	for name, id := range ids {
+		b58, err := IDToBase58(id)
+		if err != nil {
+			t.Fatalf("%s: IDToBase58: %v", name, err)
+		}
+		cid, err := IDToCID(id)
+		if err != nil {
+			t.Fatalf("%s: IDToCID: %v", name, err)
+		}
+		for _, s := range []string{id, strings.ToUpper(id), b58, cid} {
+			if got, err := NormalizeID(s); err != nil || got != id {
+				t.Errorf("%s: NormalizeID(%s) = %s, %v; want %s", name, s, got, err, id)
+			}
+		}
+	}
+
+	// 无前缀ID的各种写法与带前缀的形式一致
+	b58, _ := IDToBase58(legacy)
+	cid, _ := IDToCID(legacy)
+	for _, s := range []string{legacy, Base58Encode(mustHex(legacy)), b58, cid} {
+		if got, err := NormalizeID(s); err != nil || got != want {
+			t.Errorf("legacy: NormalizeID(%s) = %s, %v; want %s", s, got, err, want)
+		}
+	}
+}
+
+func TestNormalizeIDErrors(t *testing.T) {
+	sum := mustHex(emptySums[SHA2_256])
+	cid := func(codec byte) string {
+		buf := append([]byte{cidV1, codec, SHA2_256, 32}, sum...)
+		return "z" + Base58Encode(buf)
+	}
+	tests := []string{
+		"",
+		"xyz",
+		"1620" + emptySums[SHA2_256][:62], // 摘要短
+		cid(0x70),                         // dag-pb
+		cid(0x71),                         // dag-cbor
+		"z" + Base58Encode([]byte{cidV1}), // 无编解码码
+		Base58Encode([]byte{0x11, 0x14, 1, 2, 3}),
+	}
+	for _, s := range tests {
+		if got, err := NormalizeID(s); err == nil {
+			t.Errorf("NormalizeID(%q) = %s, want error", s, got)
+		}
+	}
+	if got, err := NormalizeID(cid(cidRaw)); err != nil || got != EncodeID(SHA2_256, sum) {
+		t.Errorf("raw cid: NormalizeID = %s, %v", got, err)
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
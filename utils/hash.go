@@ -0,0 +1,202 @@
+// Package utils 提供存档服务通用的辅助工具。
+//
+// 文档ID（DID）由文档内容的哈希摘要构成，前置 multihash 风格的算法前缀：
+//
+//	[算法码:1][摘要长度:1][摘要...]
+//
+// 前缀使得不同部署可选用不同的哈希算法，而查找时可接受任一已注册的算法。
+// 无前缀的32字节ID视为默认的 SHA3-256 摘要（兼容早期设计）。
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+	"lukechampine.com/blake3"
+)
+
+// 哈希算法码（与 multihash 编码表一致）。
+const (
+	SHA2_256 byte = 0x12
+	SHA3_256 byte = 0x16
+	BLAKE3   byte = 0x1e
+)
+
+// 无前缀ID的摘要长度（SHA3-256）。
+const LegacySize = 32
+
+var (
+	// ErrUnknownAlgo 未注册的哈希算法。
+	ErrUnknownAlgo = errors.New("unknown hash algorithm")
+
+	// ErrBadID 文档ID格式错误。
+	ErrBadID = errors.New("invalid document id")
+)
+
+// Algo 哈希算法定义。
+type Algo struct {
+	Code byte             // 算法码
+	Name string           // 算法名称，如 sha3-256
+	Size int              // 摘要长度（字节）
+	New  func() hash.Hash // 哈希器创建函数
+}
+
+var (
+	mu      sync.RWMutex
+	byCode  = make(map[byte]*Algo)
+	byName  = make(map[string]*Algo)
+	current *Algo
+)
+
+func init() {
+	Register(SHA3_256, "sha3-256", sha3.New256)
+	Register(SHA2_256, "sha2-256", sha256.New)
+	Register(BLAKE3, "blake3", func() hash.Hash { return blake3.New(32, nil) })
+	current = byCode[SHA3_256]
+}
+
+// Register 注册一个哈希算法。
+// 同码或同名的重复注册会覆盖之前的定义。
+func Register(code byte, name string, fn func() hash.Hash) {
+	a := &Algo{
+		Code: code,
+		Name: strings.ToLower(name),
+		Size: fn().Size(),
+		New:  fn,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+
+	byCode[code] = a
+	byName[a.Name] = a
+}
+
+// SetDefault 设置新文档ID采用的哈希算法。
+// name 为已注册的算法名称，如 sha2-256。
+func SetDefault(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	a, ok := byName[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownAlgo, name)
+	}
+	current = a
+	return nil
+}
+
+// Default 返回当前的默认算法。
+func Default() *Algo {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// AlgoNames 返回已注册的算法名称（已排序）。
+func AlgoNames() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]string, 0, len(byName))
+	for n := range byName {
+		list = append(list, n)
+	}
+	slices.Sort(list)
+	return list
+}
+
+// Lookup 按算法码获取算法定义。
+func Lookup(code byte) (*Algo, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := byCode[code]
+	return a, ok
+}
+
+// 按算法名称获取算法定义。
+func lookupName(name string) (*Algo, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := byName[strings.ToLower(name)]
+	return a, ok
+}
+
+// HashSHA3 计算数据的 SHA3-256 摘要。
+func HashSHA3(data []byte) []byte {
+	sum := sha3.Sum256(data)
+	return sum[:]
+}
+
+// HashID 以默认算法计算数据的文档ID（含算法前缀）。
+func HashID(data []byte) string {
+	a := Default()
+	h := a.New()
+	h.Write(data)
+	return EncodeID(a.Code, h.Sum(nil))
+}
+
+// HashReaderID 以默认算法计算流数据的文档ID（含算法前缀）。
+func HashReaderID(r io.Reader) (string, error) {
+	a := Default()
+	h := a.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return EncodeID(a.Code, h.Sum(nil)), nil
+}
+
+// EncodeID 编码算法码和摘要为十六进制的文档ID。
+func EncodeID(code byte, sum []byte) string {
+	buf := make([]byte, 0, 2+len(sum))
+	buf = append(buf, code, byte(len(sum)))
+	return hex.EncodeToString(append(buf, sum...))
+}
+
+// ParseID 解析文档ID，返回其算法定义和摘要。
+// 接受任一已注册算法的前缀ID，以及无前缀的 SHA3-256 ID。
+func ParseID(id string) (*Algo, []byte, error) {
+	raw, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrBadID, err)
+	}
+	if len(raw) == LegacySize {
+		a, _ := Lookup(SHA3_256)
+		return a, raw, nil
+	}
+	if len(raw) < 2 {
+		return nil, nil, ErrBadID
+	}
+	a, ok := Lookup(raw[0])
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: 0x%02x", ErrUnknownAlgo, raw[0])
+	}
+	if int(raw[1]) != a.Size || len(raw)-2 != a.Size {
+		return nil, nil, fmt.Errorf("%w: bad %s digest size", ErrBadID, a.Name)
+	}
+	return a, raw[2:], nil
+}
+
+// VerifyID 检查数据是否与文档ID匹配。
+// 采用ID自身标识的算法计算，与当前默认算法无关。
+func VerifyID(id string, r io.Reader) (bool, error) {
+	a, sum, err := ParseID(id)
+	if err != nil {
+		return false, err
+	}
+	h := a.New()
+
+	if _, err := io.Copy(h, r); err != nil {
+		return false, err
+	}
+	return bytes.Equal(h.Sum(nil), sum), nil
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 空数据的已知摘要。
+var emptySums = map[byte]string{
+	SHA3_256: "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+	SHA2_256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	BLAKE3:   "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262",
+}
+
+func TestRegistry(t *testing.T) {
+	want := []string{"blake3", "sha2-256", "sha3-256"}
+	if got := AlgoNames(); !slicesEqual(got, want) {
+		t.Errorf("AlgoNames() = %v, want %v", got, want)
+	}
+	for code, sum := range emptySums {
+		a, ok := Lookup(code)
+		if !ok {
+			t.Fatalf("Lookup(0x%02x) failed", code)
+		}
+		h := a.New()
+		if got := hex.EncodeToString(h.Sum(nil)); got != sum || a.Size != 32 {
+			t.Errorf("%s: empty digest %s (size %d), want %s", a.Name, got, a.Size, sum)
+		}
+	}
+	if err := SetDefault("MD5"); !errors.Is(err, ErrUnknownAlgo) {
+		t.Errorf("SetDefault(MD5) = %v, want ErrUnknownAlgo", err)
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	defer SetDefault("sha3-256")
+	data := []byte("archives")
+
+	for _, name := range AlgoNames() {
+		t.Run(name, func(t *testing.T) {
+			if err := SetDefault(strings.ToUpper(name)); err != nil {
+				t.Fatal(err)
+			}
+			id := HashID(data)
+			rid, err := HashReaderID(bytes.NewReader(data))
+			if err != nil || rid != id {
+				t.Fatalf("HashReaderID = %s, %v; want %s", rid, err, id)
+			}
+			a, sum, err := ParseID(id)
+			if err != nil {
+				t.Fatalf("ParseID(%s): %v", id, err)
+			}
+			if a.Name != name || EncodeID(a.Code, sum) != id {
+				t.Errorf("ParseID(%s) = %s %x", id, a.Name, sum)
+			}
+			// 校验与当前默认算法无关
+			SetDefault("sha2-256")
+			if ok, err := VerifyID(id, bytes.NewReader(data)); !ok || err != nil {
+				t.Errorf("VerifyID = %v, %v; want match", ok, err)
+			}
+			if ok, _ := VerifyID(id, strings.NewReader("other")); ok {
+				t.Error("VerifyID matched other data")
+			}
+		})
+	}
+}
+
+func TestLegacyID(t *testing.T) {
+	data := []byte("archives")
+	id := hex.EncodeToString(HashSHA3(data))
+
+	a, sum, err := ParseID(id)
+	if err != nil || a.Code != SHA3_256 || hex.EncodeToString(sum) != id {
+		t.Fatalf("ParseID(legacy) = %v, %x, %v", a, sum, err)
+	}
+	if ok, err := VerifyID(id, bytes.NewReader(data)); !ok || err != nil {
+		t.Errorf("VerifyID(legacy) = %v, %v", ok, err)
+	}
+	// 前缀形式与无前缀形式的摘要相同
+	if got := HashID(data); got != "1620"+id {
+		t.Errorf("HashID = %s, want prefixed %s", got, id)
+	}
+}
+
+func TestParseIDErrors(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"xyz", ErrBadID},
+		{"", ErrBadID},
+		{"16", ErrBadID},
+		{"1620" + sum[:62], ErrBadID},          // 摘要短
+		{"1621" + sum + "00", ErrBadID},        // 长度字节不符
+		{"1620" + sum + "00", ErrBadID},        // 摘要长
+		{"1120" + sum, ErrUnknownAlgo},         // SHA1 未注册
+		{sum[:62], ErrUnknownAlgo},             // 31字节，首字节作为算法码
+		{"1e20" + sum[:62] + "0000", ErrBadID}, // BLAKE3，长度不符
+	}
+	for _, tt := range tests {
+		if _, _, err := ParseID(tt.id); !errors.Is(err, tt.want) {
+			t.Errorf("ParseID(%q) = %v, want %v", tt.id, err, tt.want)
+		}
+	}
+}
+
+func slicesEqual(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LayoutVersion 分片布局的当前格式版本。
+// 布局格式变更时递增，旧版本的布局仍应可读。
+const LayoutVersion = 1
+
+var (
+	// ErrLayoutVersion 不支持的布局版本。
+	ErrLayoutVersion = errors.New("unsupported layout version")
+
+	// ErrLayout 布局与数据不符。
+	ErrLayout = errors.New("layout mismatch")
+)
+
+// Layout 文档的分片布局。
+// 记录于元信息的 layout 条目，供独立的校验工具使用。
+// 分片哈希的默克尔根作为分片列表的校验和，Sum 则覆盖其余全部字段。
+type Layout struct {
+	Version int      `json:"version"` // 格式版本
+	Algo    string   `json:"algo"`    // 哈希算法名称
+	Size    int64    `json:"size"`    // 数据总长度
+	Piece   int64    `json:"piece"`   // 分片大小
+	Pieces  []string `json:"pieces"`  // 各分片的叶子哈希（十六进制）
+	Root    string   `json:"root"`    // 分片哈希的默克尔根
+	Total   string   `json:"total"`   // 整体数据的哈希
+	Sum     string   `json:"sum"`     // 布局自身的校验和
+}
+
+// NewLayout 计算数据的分片布局。
+// piece 为0时采用默认分片大小。
+func NewLayout(a *Algo, r io.ReaderAt, size, piece int64) (*Layout, error) {
+	if piece == 0 {
+		piece = PieceSize
+	}
+	leaves, err := PieceHashes(a, r, size, piece)
+	if err != nil {
+		return nil, err
+	}
+	total, err := HashRange(a, r, 0, size)
+	if err != nil {
+		return nil, err
+	}
+	l := &Layout{
+		Version: LayoutVersion,
+		Algo:    a.Name,
+		Size:    size,
+		Piece:   piece,
+		Pieces:  make([]string, len(leaves)),
+		Root:    hex.EncodeToString(MerkleRoot(a, leaves)),
+		Total:   hex.EncodeToString(total),
+	}
+	for i, h := range leaves {
+		l.Pieces[i] = hex.EncodeToString(h)
+	}
+	l.Sum = l.checksum(a)
+
+	return l, nil
+}
+
+// Check 检查布局自身的一致性（不读取数据）。
+// 包括版本、算法、分片数量、默克尔根和校验和。
+func (l *Layout) Check() error {
+	_, _, err := l.decode()
+	return err
+}
+
+// Verify 以数据校验布局。
+// 返回第一个不符的分片，或整体哈希的不符。数据长于 Size 也视为不符。
+func (l *Layout) Verify(r io.ReaderAt) error {
+	a, leaves, err := l.decode()
+	if err != nil {
+		return err
+	}
+	got, err := PieceHashes(a, r, l.Size, l.Piece)
+	if err != nil {
+		return err
+	}
+	for i := range leaves {
+		if !bytes.Equal(got[i], leaves[i]) {
+			return fmt.Errorf("%w: piece %d", ErrLayout, i)
+		}
+	}
+	total, err := HashRange(a, r, 0, l.Size)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(total) != l.Total {
+		return fmt.Errorf("%w: total hash", ErrLayout)
+	}
+	var b [1]byte
+	if n, _ := r.ReadAt(b[:], l.Size); n > 0 {
+		return fmt.Errorf("%w: data longer than %d", ErrLayout, l.Size)
+	}
+	return nil
+}
+
+// 计算布局的校验和。
+// 对象为除分片列表（已由默克尔根覆盖）和校验和之外的各字段，
+// 以JSON数组编码：顺序固定，字符串经转义。
+func (l *Layout) checksum(a *Algo) string {
+	buf, _ := json.Marshal([]any{l.Version, l.Algo, l.Size, l.Piece, l.Root, l.Total})
+	h := a.New()
+	h.Write(buf)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 解码并检查布局，返回算法和叶子哈希。
+func (l *Layout) decode() (*Algo, [][]byte, error) {
+	if l.Version != LayoutVersion {
+		return nil, nil, fmt.Errorf("%w: %d", ErrLayoutVersion, l.Version)
+	}
+	a, ok := lookupName(l.Algo)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownAlgo, l.Algo)
+	}
+	if l.Piece <= 0 || l.Size < 0 || int64(len(l.Pieces)) != (l.Size+l.Piece-1)/l.Piece {
+		return nil, nil, fmt.Errorf("%w: piece count", ErrLayout)
+	}
+	leaves := make([][]byte, len(l.Pieces))
+
+	for i, s := range l.Pieces {
+		h, err := hex.DecodeString(s)
+		if err != nil || len(h) != a.Size {
+			return nil, nil, fmt.Errorf("%w: piece %d hash", ErrLayout, i)
+		}
+		leaves[i] = h
+	}
+	if hex.EncodeToString(MerkleRoot(a, leaves)) != l.Root {
+		return nil, nil, fmt.Errorf("%w: merkle root", ErrLayout)
+	}
+	if h, err := hex.DecodeString(l.Total); err != nil || len(h) != a.Size {
+		return nil, nil, fmt.Errorf("%w: total hash", ErrLayout)
+	}
+	if l.checksum(a) != l.Sum {
+		return nil, nil, fmt.Errorf("%w: checksum", ErrLayout)
+	}
+	return a, leaves, nil
+}
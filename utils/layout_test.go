@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	data := testData(1000)
+	a, _ := Lookup(SHA2_256)
+
+	l, err := NewLayout(a, bytes.NewReader(data), int64(len(data)), 300)
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+	if l.Version != LayoutVersion || l.Algo != "sha2-256" || len(l.Pieces) != 4 {
+		t.Fatalf("layout = %+v", l)
+	}
+	// 经JSON往返（元信息中的存储形式）后仍可校验
+	buf, _ := json.Marshal(l)
+	back := new(Layout)
+	if err := json.Unmarshal(buf, back); err != nil {
+		t.Fatal(err)
+	}
+	if err := back.Check(); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	if err := back.Verify(bytes.NewReader(data)); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	bad := bytes.Clone(data)
+	bad[650]++
+	if err := l.Verify(bytes.NewReader(bad)); !errors.Is(err, ErrLayout) || err.Error() != "layout mismatch: piece 2" {
+		t.Errorf("Verify(modified) = %v", err)
+	}
+	if err := l.Verify(bytes.NewReader(data[:900])); !errors.Is(err, ErrRange) {
+		t.Errorf("Verify(short) = %v, want ErrRange", err)
+	}
+	if err := l.Verify(bytes.NewReader(append(bytes.Clone(data), 0))); !errors.Is(err, ErrLayout) {
+		t.Errorf("Verify(long) = %v, want ErrLayout", err)
+	}
+}
+
+func TestLayoutDefaultPiece(t *testing.T) {
+	l, err := NewLayout(Default(), bytes.NewReader(nil), 0, 0)
+	if err != nil || l.Piece != PieceSize || len(l.Pieces) != 0 || l.Root != "" {
+		t.Fatalf("NewLayout(empty) = %+v, %v", l, err)
+	}
+	if err := l.Verify(bytes.NewReader(nil)); err != nil {
+		t.Errorf("Verify(empty): %v", err)
+	}
+}
+
+func TestLayoutCheck(t *testing.T) {
+	data := testData(1000)
+	tests := []struct {
+		name string
+		edit func(*Layout)
+		err  error
+	}{
+		{"version", func(l *Layout) { l.Version = 2 }, ErrLayoutVersion},
+		{"algo", func(l *Layout) { l.Algo = "md5" }, ErrUnknownAlgo},
+		{"piece", func(l *Layout) { l.Piece = 0 }, ErrLayout},
+		{"piece size", func(l *Layout) { l.Piece = 299 }, ErrLayout},
+		{"size", func(l *Layout) { l.Size = 1300 }, ErrLayout},
+		{"size in piece", func(l *Layout) { l.Size = 999 }, ErrLayout},
+		{"count", func(l *Layout) { l.Pieces = l.Pieces[:3] }, ErrLayout},
+		{"hash", func(l *Layout) { l.Pieces[1] = "zz" }, ErrLayout},
+		{"swap", func(l *Layout) { l.Pieces[0], l.Pieces[1] = l.Pieces[1], l.Pieces[0] }, ErrLayout},
+		{"root", func(l *Layout) { l.Root = l.Pieces[0] }, ErrLayout},
+		{"total", func(l *Layout) { l.Total = l.Pieces[0] }, ErrLayout},
+		{"total hex", func(l *Layout) { l.Total = "zz" }, ErrLayout},
+		{"sum", func(l *Layout) { l.Sum = l.Root }, ErrLayout},
+	}
+	for _, tt := range tests {
+		l, _ := NewLayout(Default(), bytes.NewReader(data), int64(len(data)), 300)
+		tt.edit(l)
+
+		if err := l.Check(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Check() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// PieceSize 默认分片大小（256KB）。
+const PieceSize = 256 << 10
+
+// 默克尔树节点的域分隔前缀，防止叶子与中间节点混淆。
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// ErrRange 数据范围无效。
+var ErrRange = errors.New("invalid data range")
+
+// HashRange 计算数据中 [beg, beg+size) 范围的哈希。
+// 范围超出数据末尾时返回 ErrRange。
+func HashRange(a *Algo, r io.ReaderAt, beg, size int64) ([]byte, error) {
+	if beg < 0 || size < 0 {
+		return nil, ErrRange
+	}
+	h := a.New()
+	n, err := io.Copy(h, io.NewSectionReader(r, beg, size))
+
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, ErrRange
+	}
+	return h.Sum(nil), nil
+}
+
+// PieceHashes 计算数据各分片的叶子哈希。
+// total 为数据总长度，piece 为分片大小，末片可能不足。
+// 数据短于 total 时返回 ErrRange。
+func PieceHashes(a *Algo, r io.ReaderAt, total, piece int64) ([][]byte, error) {
+	if piece <= 0 || total < 0 {
+		return nil, ErrRange
+	}
+	list := make([][]byte, 0, (total+piece-1)/piece)
+
+	for beg := int64(0); beg < total; beg += piece {
+		h := a.New()
+		h.Write([]byte{leafPrefix})
+		size := min(piece, total-beg)
+
+		n, err := io.Copy(h, io.NewSectionReader(r, beg, size))
+		if err != nil {
+			return nil, err
+		}
+		if n != size {
+			return nil, ErrRange
+		}
+		list = append(list, h.Sum(nil))
+	}
+	return list, nil
+}
+
+// MerkleRoot 计算叶子哈希集的默克尔根。
+// 奇数个节点时末节点直接晋级到上层。空集返回nil。
+func MerkleRoot(a *Algo, leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+	level := leaves
+
+	for len(level) > 1 {
+		level = merkleUp(a, level)
+	}
+	return level[0]
+}
+
+// MerkleProof 构造目标叶子的默克尔证明（兄弟节点路径，自底向上）。
+// 晋级层无兄弟节点，不产生路径项。
+func MerkleProof(a *Algo, leaves [][]byte, index int) ([][]byte, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, ErrRange
+	}
+	var path [][]byte
+	level := leaves
+
+	for len(level) > 1 {
+		if sib := index ^ 1; sib < len(level) {
+			path = append(path, level[sib])
+		}
+		level = merkleUp(a, level)
+		index /= 2
+	}
+	return path, nil
+}
+
+// VerifyProof 验证叶子哈希是否属于默克尔根。
+// count 为叶子总数，用于判断各层是否存在兄弟节点。
+func VerifyProof(a *Algo, root, leaf []byte, index, count int, path [][]byte) bool {
+	if index < 0 || index >= count {
+		return false
+	}
+	sum := leaf
+
+	for ; count > 1; count = (count + 1) / 2 {
+		sib := index ^ 1
+		if sib < count {
+			if len(path) == 0 {
+				return false
+			}
+			if index&1 == 0 {
+				sum = merkleNode(a, sum, path[0])
+			} else {
+				sum = merkleNode(a, path[0], sum)
+			}
+			path = path[1:]
+		}
+		index /= 2
+	}
+	return len(path) == 0 && bytes.Equal(sum, root)
+}
+
+// 计算上一层节点。
+func merkleUp(a *Algo, level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			break
+		}
+		next = append(next, merkleNode(a, level[i], level[i+1]))
+	}
+	return next
+}
+
+// 计算中间节点哈希。
+func merkleNode(a *Algo, left, right []byte) []byte {
+	h := a.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
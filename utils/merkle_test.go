@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// 构造测试数据：n 字节的递增序列。
+func testData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func TestHashRange(t *testing.T) {
+	a := Default()
+	data := testData(100)
+	r := bytes.NewReader(data)
+
+	tests := []struct {
+		beg, size int64
+		err       error
+	}{
+		{0, 100, nil},
+		{10, 20, nil},
+		{100, 0, nil},
+		{90, 11, ErrRange},
+		{-1, 10, ErrRange},
+		{0, -1, ErrRange},
+	}
+	for _, tt := range tests {
+		sum, err := HashRange(a, r, tt.beg, tt.size)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("HashRange(%d, %d) error = %v, want %v", tt.beg, tt.size, err, tt.err)
+			continue
+		}
+		if err == nil {
+			h := a.New()
+			h.Write(data[tt.beg : tt.beg+tt.size])
+			if !bytes.Equal(sum, h.Sum(nil)) {
+				t.Errorf("HashRange(%d, %d) = %x", tt.beg, tt.size, sum)
+			}
+		}
+	}
+}
+
+func TestPieceHashes(t *testing.T) {
+	a := Default()
+	r := bytes.NewReader(testData(100))
+
+	tests := []struct {
+		total, piece int64
+		count        int
+		err          error
+	}{
+		{100, 30, 4, nil},
+		{100, 100, 1, nil},
+		{100, 1000, 1, nil},
+		{90, 30, 3, nil}, // 只取前部
+		{0, 30, 0, nil},
+		{1000, 256, 0, ErrRange}, // 数据不足
+		{101, 50, 0, ErrRange},   // 末片不足
+		{100, 0, 0, ErrRange},
+		{-1, 30, 0, ErrRange},
+	}
+	for _, tt := range tests {
+		list, err := PieceHashes(a, r, tt.total, tt.piece)
+		if !errors.Is(err, tt.err) || len(list) != tt.count {
+			t.Errorf("PieceHashes(%d, %d) = %d leaves, %v; want %d, %v",
+				tt.total, tt.piece, len(list), err, tt.count, tt.err)
+		}
+	}
+}
+
+func TestMerkleRoot(t *testing.T) {
+	a := Default()
+	l := make([][]byte, 3)
+	for i := range l {
+		l[i] = []byte{byte(i)}
+	}
+	if MerkleRoot(a, nil) != nil {
+		t.Error("MerkleRoot(empty) is not nil")
+	}
+	if got := MerkleRoot(a, l[:1]); !bytes.Equal(got, l[0]) {
+		t.Errorf("MerkleRoot(1) = %x, want the leaf", got)
+	}
+	// 第三个叶子晋级，与前两个的父节点合并
+	want := merkleNode(a, merkleNode(a, l[0], l[1]), l[2])
+	if got := MerkleRoot(a, l); !bytes.Equal(got, want) {
+		t.Errorf("MerkleRoot(3) = %x, want %x", got, want)
+	}
+}
+
+func TestMerkleProof(t *testing.T) {
+	a := Default()
+
+	for count := 1; count <= 17; count++ {
+		leaves, err := PieceHashes(a, bytes.NewReader(testData(count*10)), int64(count*10), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := MerkleRoot(a, leaves)
+
+		for i := 0; i < count; i++ {
+			name := fmt.Sprintf("%d/%d", i, count)
+			path, err := MerkleProof(a, leaves, i)
+			if err != nil {
+				t.Fatalf("%s: MerkleProof: %v", name, err)
+			}
+			if !VerifyProof(a, root, leaves[i], i, count, path) {
+				t.Errorf("%s: valid proof rejected", name)
+			}
+			if count > 1 && VerifyProof(a, root, leaves[(i+1)%count], i, count, path) {
+				t.Errorf("%s: wrong leaf accepted", name)
+			}
+			if i^1 < count && VerifyProof(a, root, leaves[i], i^1, count, path) {
+				t.Errorf("%s: wrong index accepted", name)
+			}
+			if VerifyProof(a, root, leaves[i], i, count, append(path, root)) {
+				t.Errorf("%s: extra path item accepted", name)
+			}
+			if len(path) > 0 && VerifyProof(a, root, leaves[i], i, count, path[:len(path)-1]) {
+				t.Errorf("%s: short path accepted", name)
+			}
+		}
+		if _, err := MerkleProof(a, leaves, count); !errors.Is(err, ErrRange) {
+			t.Errorf("%d: MerkleProof(out of range) = %v", count, err)
+		}
+		if VerifyProof(a, root, leaves[0], -1, count, nil) || VerifyProof(a, root, leaves[0], count, count, nil) {
+			t.Errorf("%d: index out of range accepted", count)
+		}
+	}
+}
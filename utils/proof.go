@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// NonceMinSize 挑战随机数的最小长度。
+// 过短的随机数使存储方可预先计算应答，从而无需真实持有数据。
+const NonceMinSize = 16
+
+// 挑战的规模上限。
+// 证明只需抽查少量数据，限制规模以免单个挑战耗费大量的读取和计算。
+const (
+	MaxSpans          = 64       // 范围数量上限
+	MaxChallengeBytes = 16 << 20 // 范围总长度上限（16MB）
+)
+
+var (
+	// ErrNonce 挑战随机数无效。
+	ErrNonce = errors.New("challenge nonce too short")
+
+	// ErrChallengeSize 挑战超出规模上限。
+	ErrChallengeSize = errors.New("challenge too large")
+)
+
+// Span 文档数据的一个范围。
+type Span struct {
+	Beg  int64 `json:"beg"`  // 起始偏移
+	Size int64 `json:"size"` // 范围长度
+}
+
+// Challenge 存储证明挑战。
+// 由审计方提供，存储方据此计算加盐的范围哈希作为应答。
+type Challenge struct {
+	DocID string `json:"id"`    // 目标文档ID
+	Spans []Span `json:"spans"` // 待证明的数据范围
+	Nonce []byte `json:"nonce"` // 随机盐
+}
+
+// Check 检查挑战的格式。
+// 文档ID须有效，随机数须足够长，且至少包含一个非空范围。
+// 范围的数量和总长度不可超出上限（MaxSpans, MaxChallengeBytes）。
+func (c *Challenge) Check() error {
+	if _, _, err := ParseID(c.DocID); err != nil {
+		return err
+	}
+	if len(c.Nonce) < NonceMinSize {
+		return ErrNonce
+	}
+	if len(c.Spans) == 0 {
+		return ErrRange
+	}
+	if len(c.Spans) > MaxSpans {
+		return fmt.Errorf("%w: %d spans", ErrChallengeSize, len(c.Spans))
+	}
+	var total int64
+
+	for _, s := range c.Spans {
+		if s.Beg < 0 || s.Size <= 0 {
+			return ErrRange
+		}
+		// 逐个比较，避免累加溢出
+		if s.Size > MaxChallengeBytes-total {
+			return fmt.Errorf("%w: over %d bytes", ErrChallengeSize, MaxChallengeBytes)
+		}
+		total += s.Size
+	}
+	return nil
+}
+
+// Respond 计算挑战的应答哈希。
+// 算法取自文档ID，计算方式为：
+//
+//	H(nonce || {beg:8 || size:8 || data[beg:beg+size]}...)
+//
+// 审计方持有数据（或其副本）时以同样的方式计算并比较。
+func (c *Challenge) Respond(r io.ReaderAt) ([]byte, error) {
+	if err := c.Check(); err != nil {
+		return nil, err
+	}
+	a, _, _ := ParseID(c.DocID)
+	h := a.New()
+	h.Write(c.Nonce)
+
+	var buf [16]byte
+	for _, s := range c.Spans {
+		binary.BigEndian.PutUint64(buf[:8], uint64(s.Beg))
+		binary.BigEndian.PutUint64(buf[8:], uint64(s.Size))
+		h.Write(buf[:])
+
+		n, err := io.Copy(h, io.NewSectionReader(r, s.Beg, s.Size))
+		if err != nil {
+			return nil, err
+		}
+		if n != s.Size {
+			return nil, ErrRange
+		}
+	}
+	return h.Sum(nil), nil
+}
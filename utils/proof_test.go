@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestChallengeCheck(t *testing.T) {
+	id := HashID(testData(100))
+	nonce := testData(NonceMinSize)
+	many := make([]Span, MaxSpans+1)
+	for i := range many {
+		many[i] = Span{int64(i), 1}
+	}
+
+	tests := []struct {
+		name string
+		c    Challenge
+		err  error
+	}{
+		{"ok", Challenge{id, []Span{{0, 10}, {50, 50}}, nonce}, nil},
+		{"max spans", Challenge{id, many[:MaxSpans], nonce}, nil},
+		{"max bytes", Challenge{id, []Span{{0, MaxChallengeBytes - 1}, {0, 1}}, nonce}, nil},
+		{"bad id", Challenge{"xyz", []Span{{0, 10}}, nonce}, ErrBadID},
+		{"short nonce", Challenge{id, []Span{{0, 10}}, nonce[1:]}, ErrNonce},
+		{"no spans", Challenge{id, nil, nonce}, ErrRange},
+		{"negative beg", Challenge{id, []Span{{-1, 10}}, nonce}, ErrRange},
+		{"empty span", Challenge{id, []Span{{0, 0}}, nonce}, ErrRange},
+		{"too many spans", Challenge{id, many, nonce}, ErrChallengeSize},
+		{"too many bytes", Challenge{id, []Span{{0, MaxChallengeBytes}, {0, 1}}, nonce}, ErrChallengeSize},
+		{"overflow", Challenge{id, []Span{{0, 1}, {0, 1<<63 - 1}}, nonce}, ErrChallengeSize},
+	}
+	for _, tt := range tests {
+		if err := tt.c.Check(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Check() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestChallengeRespond(t *testing.T) {
+	data := testData(100)
+	c := &Challenge{
+		DocID: HashID(data),
+		Spans: []Span{{0, 10}, {90, 10}},
+		Nonce: testData(NonceMinSize),
+	}
+	got, err := c.Respond(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Respond: %v", err)
+	}
+	// 按文档说明的方式独立计算
+	h := Default().New()
+	h.Write(c.Nonce)
+	for _, s := range c.Spans {
+		binary.Write(h, binary.BigEndian, []int64{s.Beg, s.Size})
+		h.Write(data[s.Beg : s.Beg+s.Size])
+	}
+	if !bytes.Equal(got, h.Sum(nil)) {
+		t.Errorf("Respond = %x, want %x", got, h.Sum(nil))
+	}
+
+	// 随机数不同，应答不同
+	c2 := *c
+	c2.Nonce = testData(NonceMinSize + 1)
+	if other, _ := c2.Respond(bytes.NewReader(data)); bytes.Equal(other, got) {
+		t.Error("response does not depend on nonce")
+	}
+	// 数据被修改，应答不同
+	bad := bytes.Clone(data)
+	bad[95]++
+	if other, _ := c.Respond(bytes.NewReader(bad)); bytes.Equal(other, got) {
+		t.Error("response does not depend on data")
+	}
+	// 范围超出数据
+	c2 = *c
+	c2.Spans = []Span{{95, 10}}
+	if _, err := c2.Respond(bytes.NewReader(data)); !errors.Is(err, ErrRange) {
+		t.Errorf("Respond(past end) = %v, want ErrRange", err)
+	}
+}